@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/younsl/cocd/pkg/config"
+	ghclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/scanner"
+	"github.com/younsl/cocd/pkg/workflow"
+)
+
+var approveCmd = &cobra.Command{
+	Use:   "approve [repository run-id]",
+	Short: "Approve pending deployments of workflow runs",
+	Long: `approve approves the pending environments you can approve, either of one run
+given as arguments or of every approval waiting run matching --env and --branch.
+
+The runs are listed before anything is approved. Pass --dry-run to stop there,
+or --yes to skip the confirmation prompt, which is required without a terminal.`,
+	Example: `  cocd approve --org my-org api 1234567890 --comment "Release 1.2.0"
+  cocd approve --org my-org --env production --branch main --dry-run
+  cocd approve --org my-org --env production --branch main --yes`,
+	// main prints errors and picks the exit code
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runApprove,
+}
+
+func init() {
+	addRunSelectorFlags(approveCmd)
+	approveCmd.Flags().StringP("comment", "m", "", "Comment added to the review")
+}
+
+// approval is a run and the pending environments that will be approved
+type approval struct {
+	job         scanner.JobStatus
+	deployments []*ghclient.PendingDeployment
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	selector, err := parseRunSelector(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	mon, err := newMonitor(cfg)
+	if err != nil {
+		return err
+	}
+	defer mon.GetClient().Close()
+	service := workflow.NewService(mon.GetClient(), cfg.Approval.CommentTemplate, cfg.Monitor.Timezone)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	approvals, err := findApprovals(ctx, cfg, mon, service, selector)
+	if err != nil {
+		return err
+	}
+	if len(approvals) == 0 {
+		return &exitError{code: exitCodeNoJobs, err: fmt.Errorf("no matching runs are waiting on an environment you can approve")}
+	}
+
+	out := cmd.OutOrStdout()
+	for _, a := range approvals {
+		fmt.Fprintf(out, "%s: %s\n", describeRun(a.job), strings.Join(workflow.EnvironmentNames(a.deployments), ", "))
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Fprintf(out, "Dry run: %d runs would be approved\n", len(approvals))
+		return nil
+	}
+	if err := confirm(cmd, fmt.Sprintf("Approve %d runs?", len(approvals))); err != nil {
+		return err
+	}
+
+	comment, _ := cmd.Flags().GetString("comment")
+	failed := 0
+	for _, a := range approvals {
+		if err := service.Approve(ctx, a.job, workflow.EnvironmentIDs(a.deployments), comment); err != nil {
+			failed++
+			fmt.Fprintf(cmd.ErrOrStderr(), "Failed to approve %s: %v\n", describeRun(a.job), err)
+			continue
+		}
+		fmt.Fprintf(out, "Approved %s\n", describeRun(a.job))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d approvals failed", failed, len(approvals))
+	}
+	return nil
+}
+
+// findApprovals resolves the selector to runs with environments the current user can approve
+func findApprovals(ctx context.Context, cfg *config.Config, mon *monitor.Monitor, service *workflow.Service, selector runSelector) ([]approval, error) {
+	if selector.isSingleRun() {
+		job, err := service.GetRun(ctx, selector.repo, selector.runID)
+		if err != nil {
+			return nil, err
+		}
+		if selector.branch != "" && job.Branch != selector.branch {
+			return nil, nil
+		}
+
+		deployments, err := service.ApprovableDeployments(ctx, job, selector.environment)
+		if err != nil {
+			return nil, err
+		}
+		if len(deployments) == 0 {
+			return nil, nil
+		}
+		return []approval{{job: job, deployments: deployments}}, nil
+	}
+
+	jobs, err := mon.GetPendingJobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan jobs: %w", err)
+	}
+
+	var approvals []approval
+	for _, job := range jobs {
+		if !selector.match(job, cfg) || !job.IsApprovable() {
+			continue
+		}
+
+		deployments, err := service.ApprovableDeployments(ctx, job, selector.environment)
+		if err != nil {
+			// The run may have been approved or cancelled since the scan
+			if !errors.Is(err, workflow.ErrNoPendingDeployments) {
+				return nil, err
+			}
+			continue
+		}
+		if len(deployments) > 0 {
+			approvals = append(approvals, approval{job: job, deployments: deployments})
+		}
+	}
+	return approvals, nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/younsl/cocd/pkg/config"
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/scanner"
+	"github.com/younsl/cocd/pkg/workflow"
+)
+
+// activeStatuses are the run statuses that can still be cancelled
+var activeStatuses = map[string]bool{
+	"queued":      true,
+	"in_progress": true,
+	"waiting":     true,
+	"pending":     true,
+	"requested":   true,
+}
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel [repository run-id]",
+	Short: "Cancel workflow runs",
+	Long: `cancel cancels one run given as arguments, or every queued, running or
+waiting run matching --env and --branch.
+
+GitHub only reports the environment of a run while it waits for an approval, so
+--env selects waiting runs only. Queued and running runs never match it; select
+them with --branch or by run ID.
+
+The runs are listed before anything is cancelled. Pass --dry-run to stop there,
+or --yes to skip the confirmation prompt, which is required without a terminal.`,
+	Example: `  cocd cancel --org my-org api 1234567890
+  cocd cancel --org my-org --branch feature/broken --dry-run`,
+	// main prints errors and picks the exit code
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runCancel,
+}
+
+func init() {
+	addRunSelectorFlags(cancelCmd)
+}
+
+func runCancel(cmd *cobra.Command, args []string) error {
+	selector, err := parseRunSelector(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	mon, err := newMonitor(cfg)
+	if err != nil {
+		return err
+	}
+	defer mon.GetClient().Close()
+	service := workflow.NewService(mon.GetClient(), cfg.Approval.CommentTemplate, cfg.Monitor.Timezone)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	jobs, err := findCancellations(ctx, cfg, mon, service, selector)
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		return &exitError{code: exitCodeNoJobs, err: fmt.Errorf("no matching runs can be cancelled")}
+	}
+
+	out := cmd.OutOrStdout()
+	for _, job := range jobs {
+		fmt.Fprintf(out, "%s: %s\n", describeRun(job), job.Status)
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Fprintf(out, "Dry run: %d runs would be cancelled\n", len(jobs))
+		return nil
+	}
+	if err := confirm(cmd, fmt.Sprintf("Cancel %d runs?", len(jobs))); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, job := range jobs {
+		err := service.Cancel(ctx, job)
+		switch {
+		case errors.Is(err, workflow.ErrCancelInProgress):
+			fmt.Fprintf(out, "Cancelling %s: %v\n", describeRun(job), err)
+		case err != nil:
+			failed++
+			fmt.Fprintf(cmd.ErrOrStderr(), "Failed to cancel %s: %v\n", describeRun(job), err)
+		default:
+			fmt.Fprintf(out, "Cancelled %s\n", describeRun(job))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d cancellations failed", failed, len(jobs))
+	}
+	return nil
+}
+
+// findCancellations resolves the selector to runs that have not completed yet
+func findCancellations(ctx context.Context, cfg *config.Config, mon *monitor.Monitor, service *workflow.Service, selector runSelector) ([]scanner.JobStatus, error) {
+	if selector.isSingleRun() {
+		if selector.environment != "" {
+			return nil, &exitError{code: exitCodeConfig, err: fmt.Errorf("--env selects scanned runs and cannot be combined with a run ID")}
+		}
+
+		job, err := service.GetRun(ctx, selector.repo, selector.runID)
+		if err != nil {
+			return nil, err
+		}
+		if !activeStatuses[job.Status] {
+			return nil, fmt.Errorf("%s has already finished (%s)", describeRun(job), job.Status)
+		}
+		if selector.branch != "" && job.Branch != selector.branch {
+			return nil, nil
+		}
+		return []scanner.JobStatus{job}, nil
+	}
+
+	if selector.environment != "" {
+		fmt.Fprintln(os.Stderr, "Note: --env only matches waiting runs, queued and running runs are not selected")
+	}
+
+	recentJobs, err := mon.GetRecentJobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan jobs: %w", err)
+	}
+
+	var jobs []scanner.JobStatus
+	for _, job := range recentJobs {
+		if activeStatuses[job.Status] && selector.match(job, cfg) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
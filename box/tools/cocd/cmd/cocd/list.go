@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/younsl/cocd/pkg/output"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print approval waiting or recent jobs once and exit",
+	Long: `list scans the organization once and prints the jobs without starting the TUI,
+so it can be used from scripts and cron jobs without a terminal.
+
+Exit codes:
+  0  jobs were listed
+  1  scanning failed
+  2  invalid configuration or flags
+  3  no jobs were found (only with --exit-status)`,
+	Example: `  cocd list --org my-org
+  cocd list --org my-org --recent --format json
+  cocd list --org my-org --filter "branch:main" --exit-status`,
+	Args: cobra.NoArgs,
+	// main prints errors and picks the exit code
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runList,
+}
+
+func init() {
+	listCmd.Flags().StringP("format", "f", string(output.FormatTable), "Output format: table, json, yaml or csv")
+	listCmd.Flags().Bool("recent", false, "List recent jobs instead of approval waiting jobs")
+	listCmd.Flags().String("filter", "", "Only list jobs matching a filter query, e.g. \"branch:main actor:/^bot-/\"")
+	listCmd.Flags().String("sort", "", "Sort by repository, workflow, status, branch, actor, age or \"run number\"")
+	listCmd.Flags().Bool("desc", false, "Reverse the sort order")
+	listCmd.Flags().Bool("exit-status", false, "Exit with status 3 when no jobs are found")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	formatName, _ := cmd.Flags().GetString("format")
+	format, err := output.ParseFormat(formatName)
+	if err != nil {
+		return &exitError{code: exitCodeConfig, err: err}
+	}
+
+	query, _ := cmd.Flags().GetString("filter")
+	filter, err := scanner.ParseFilter(query)
+	if err != nil {
+		return &exitError{code: exitCodeConfig, err: fmt.Errorf("invalid filter: %w", err)}
+	}
+
+	sortName, _ := cmd.Flags().GetString("sort")
+	sortKey, err := parseSortKey(sortName)
+	if err != nil {
+		return &exitError{code: exitCodeConfig, err: err}
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	mon, err := newMonitor(cfg)
+	if err != nil {
+		return err
+	}
+	defer mon.GetClient().Close()
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	var jobs []scanner.JobStatus
+	if recent, _ := cmd.Flags().GetBool("recent"); recent {
+		jobs, err = mon.GetRecentJobs(ctx)
+	} else {
+		jobs, err = mon.GetPendingJobs(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to scan jobs: %w", err)
+	}
+	for _, repoErr := range mon.RepositoryErrors() {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to scan %v\n", repoErr)
+	}
+
+	jobs = filter.Apply(jobs)
+	descending, _ := cmd.Flags().GetBool("desc")
+	scanner.SortJobs(jobs, sortKey, descending)
+
+	if err := output.WriteJobs(cmd.OutOrStdout(), jobs, format); err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+
+	if exitStatus, _ := cmd.Flags().GetBool("exit-status"); exitStatus && len(jobs) == 0 {
+		return &exitError{code: exitCodeNoJobs}
+	}
+	return nil
+}
+
+// parseSortKey validates a --sort value. An empty value keeps the scan order.
+func parseSortKey(name string) (scanner.SortKey, error) {
+	for _, key := range scanner.SortKeys {
+		if string(key) == name {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort key %q", name)
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/younsl/cocd/pkg/config"
 	"github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/history"
 	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/remote"
 	"github.com/younsl/cocd/pkg/tui"
 	"golang.org/x/term"
 )
@@ -18,10 +23,43 @@ var (
 	date    = "unknown"
 )
 
+// Exit codes returned to scripts
+const (
+	exitCodeError  = 1 // Scanning or API failure
+	exitCodeConfig = 2 // Invalid configuration or flags
+	exitCodeNoJobs = 3 // Nothing found, only with list --exit-status
+)
+
+// exitError carries the process exit code for an error. A nil err exits
+// without printing anything.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		code := exitCodeError
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.code
+			err = exitErr.err
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(code)
 	}
 }
 
@@ -35,12 +73,16 @@ that are waiting for approval in production environments.`,
 }
 
 func init() {
-	rootCmd.Flags().StringP("config", "c", "", "config file path")
-	rootCmd.Flags().StringP("token", "t", "", "GitHub token")
-	rootCmd.Flags().StringP("base-url", "u", "", "GitHub base URL (for GitHub Enterprise)")
-	rootCmd.Flags().StringP("org", "o", "", "GitHub organization")
-	rootCmd.Flags().StringP("repo", "r", "", "GitHub repository (optional, if not specified monitors all repos in org)")
+	// Persistent so that subcommands share the connection settings
+	rootCmd.PersistentFlags().StringP("config", "c", "", "config file path")
+	rootCmd.PersistentFlags().StringP("token", "t", "", "GitHub token")
+	rootCmd.PersistentFlags().StringP("base-url", "u", "", "GitHub base URL (for GitHub Enterprise)")
+	rootCmd.PersistentFlags().StringP("org", "o", "", "GitHub organization")
+	rootCmd.PersistentFlags().StringP("repo", "r", "", "GitHub repository (optional, if not specified monitors all repos in org)")
 	rootCmd.Flags().IntP("interval", "i", 5, "Refresh interval in seconds")
+	rootCmd.Flags().StringP("server", "s", "", "URL of a cocd server to show instead of scanning GitHub (see cocd serve --listen)")
+	
+	rootCmd.AddCommand(listCmd, approveCmd, cancelCmd, watchCmd, serveCmd)
 }
 
 func run(cmd *cobra.Command, args []string) error {
@@ -58,51 +100,33 @@ func run(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Continuing anyway...\n")
 	}
 	
-	cfg, err := config.Load()
+	cfg, err := readConfig(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
 	}
-
-	if token, _ := cmd.Flags().GetString("token"); token != "" {
-		cfg.GitHub.Token = token
-	}
-	if baseURL, _ := cmd.Flags().GetString("base-url"); baseURL != "" {
-		cfg.GitHub.BaseURL = baseURL
+	if serverURL, _ := cmd.Flags().GetString("server"); serverURL != "" {
+		cfg.Remote.ServerURL = serverURL
 	}
-	if org, _ := cmd.Flags().GetString("org"); org != "" {
-		cfg.GitHub.Org = org
+	if cfg.Remote.ServerURL != "" {
+		return runRemote(cfg)
 	}
-	if repo, _ := cmd.Flags().GetString("repo"); repo != "" {
-		cfg.GitHub.Repo = repo
+	if cfg.GitHub.Org == "" {
+		return &exitError{code: exitCodeConfig, err: fmt.Errorf("GitHub organization is required")}
 	}
 	if interval, _ := cmd.Flags().GetInt("interval"); interval != 0 {
 		cfg.Monitor.Interval = interval
 	}
 
-	if cfg.GitHub.Org == "" {
-		return fmt.Errorf("GitHub organization is required")
-	}
-
-	var client *github.Client
-	if cfg.GitHub.Repo != "" {
-		client, err = github.NewClient(
-			cfg.GitHub.Token,
-			cfg.GitHub.BaseURL,
-			cfg.GitHub.Org,
-			cfg.GitHub.Repo,
-		)
-	} else {
-		client, err = github.NewClient(
-			cfg.GitHub.Token,
-			cfg.GitHub.BaseURL,
-			cfg.GitHub.Org,
-		)
-	}
+	mon, err := newMonitor(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create GitHub client: %w", err)
+		return err
+	}
+	defer mon.GetClient().Close()
+	
+	store := openHistory(cfg)
+	if store != nil {
+		defer store.Close()
 	}
-
-	mon := monitor.NewMonitor(client, cfg.Monitor.Interval)
 	
 	tuiConfig := &tui.AppConfig{
 		ServerURL:   cfg.GitHub.BaseURL,
@@ -110,6 +134,8 @@ func run(cmd *cobra.Command, args []string) error {
 		Repo:        cfg.GitHub.Repo,
 		Timezone:    cfg.Monitor.Timezone,
 		Version:     version,
+		CommentTemplate: cfg.Approval.CommentTemplate,
+		History:     store,
 	}
 	
 	// Use Bubble Tea instead of tview for better key handling
@@ -122,6 +148,127 @@ func run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runRemote runs the TUI on the scans of a cocd server. The organization and
+// scan interval are the server's, approvals are sent with the local token.
+func runRemote(cfg *config.Config) error {
+	token, err := cfg.GitHub.UserToken()
+	if err != nil {
+		return &exitError{code: exitCodeConfig, err: fmt.Errorf("remote mode needs a user token (github.token / GITHUB_TOKEN): %w", err)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := remote.NewClient(ctx, cfg.Remote.ServerURL, token)
+	if err != nil {
+		return &exitError{code: exitCodeConfig, err: err}
+	}
+	info := client.Info()
+
+	store := openHistory(cfg)
+	if store != nil {
+		defer store.Close()
+	}
+
+	tuiConfig := &tui.AppConfig{
+		ServerURL:       info.GitHubURL,
+		Org:             info.Organization,
+		Timezone:        cfg.Monitor.Timezone,
+		Version:         version,
+		CommentTemplate: cfg.Approval.CommentTemplate,
+		History:         store,
+	}
+
+	if err := tui.RunBubbleApp(tui.NewRemoteMonitorAdapter(client), tuiConfig); err != nil {
+		return fmt.Errorf("failed to run application: %w", err)
+	}
+	return nil
+}
+
+// openHistory opens the job history of the TUI. It returns nil when the history
+// is disabled or cannot be opened, for example while another TUI is using it.
+func openHistory(cfg *config.Config) *history.Store {
+	if !cfg.History.Enabled {
+		return nil
+	}
+
+	retention := time.Duration(cfg.History.RetentionDays) * 24 * time.Hour
+	store, err := history.Open(cfg.History.File, retention)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: job history is not saved: %v\n", err)
+		return nil
+	}
+	return store
+}
+
+// loadConfig loads the config file and applies the connection flags shared by all commands
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := readConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.GitHub.Org == "" {
+		return nil, &exitError{code: exitCodeConfig, err: fmt.Errorf("GitHub organization is required")}
+	}
+
+	return cfg, nil
+}
+
+// readConfig is loadConfig without requiring an organization, which a remote TUI gets from the server
+func readConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, &exitError{code: exitCodeConfig, err: fmt.Errorf("failed to load config: %w", err)}
+	}
+
+	if token, _ := cmd.Flags().GetString("token"); token != "" {
+		cfg.GitHub.SetToken(token)
+	}
+	if baseURL, _ := cmd.Flags().GetString("base-url"); baseURL != "" {
+		cfg.GitHub.BaseURL = baseURL
+	}
+	if org, _ := cmd.Flags().GetString("org"); org != "" {
+		cfg.GitHub.Org = org
+	}
+	if repo, _ := cmd.Flags().GetString("repo"); repo != "" {
+		cfg.GitHub.Repo = repo
+	}
+
+	return cfg, nil
+}
+
+// newMonitor creates a monitor for the configured organization or repository
+func newMonitor(cfg *config.Config) (*monitor.Monitor, error) {
+	opts := github.ClientOptions{
+		Token:             cfg.GitHub.Token,
+		TokenRefresh:      cfg.GitHub.ReadToken,
+		BaseURL:           cfg.GitHub.BaseURL,
+		Org:               cfg.GitHub.Org,
+		Repo:              cfg.GitHub.Repo,
+		CacheFile:         cfg.GitHub.CacheFile,
+		RequestsPerSecond: cfg.Monitor.RequestsPerSecond,
+	}
+	if cfg.GitHub.App.Enabled() {
+		opts.App = &github.AppCredentials{
+			AppID:          cfg.GitHub.App.ID,
+			InstallationID: cfg.GitHub.App.InstallationID,
+			PrivateKeyFile: cfg.GitHub.App.PrivateKeyFile,
+		}
+	}
+
+	client, err := github.NewClientWithOptions(opts)
+	if err != nil {
+		return nil, &exitError{code: exitCodeConfig, err: fmt.Errorf("failed to create GitHub client: %w", err)}
+	}
+
+	return monitor.NewMonitorWithOptions(client, monitor.Options{
+		Interval: cfg.Monitor.Interval,
+		Workers:  cfg.Monitor.Workers,
+		Strategy: cfg.Monitor.ScanStrategy,
+	}), nil
+}
+
 func isTerminal() bool {
 	return term.IsTerminal(int(os.Stdin.Fd()))
 }
\ No newline at end of file
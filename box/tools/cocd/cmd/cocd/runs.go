@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/younsl/cocd/pkg/config"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+// runSelector picks the workflow runs acted on by approve and cancel, either one
+// run given as arguments or every scanned run matching the selector flags
+type runSelector struct {
+	repo        string // Set when a run was given as arguments
+	runID       int64
+	environment string
+	branch      string
+}
+
+// addRunSelectorFlags registers the flags shared by approve and cancel
+func addRunSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().String("env", "", "Only act on runs waiting on this environment")
+	cmd.Flags().String("branch", "", "Only act on runs of this branch")
+	cmd.Flags().Bool("dry-run", false, "Print the matching runs without changing them")
+	cmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+}
+
+// parseRunSelector reads the run arguments and selector flags
+func parseRunSelector(cmd *cobra.Command, args []string) (runSelector, error) {
+	var selector runSelector
+	selector.environment, _ = cmd.Flags().GetString("env")
+	selector.branch, _ = cmd.Flags().GetString("branch")
+
+	switch len(args) {
+	case 0:
+		if selector.environment == "" && selector.branch == "" {
+			return selector, &exitError{code: exitCodeConfig, err: fmt.Errorf("specify a repository and run ID, or select runs with --env or --branch")}
+		}
+	case 2:
+		runID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return selector, &exitError{code: exitCodeConfig, err: fmt.Errorf("invalid run ID %q", args[1])}
+		}
+		selector.repo = args[0]
+		selector.runID = runID
+	default:
+		return selector, &exitError{code: exitCodeConfig, err: fmt.Errorf("expected a repository and a run ID, got %d arguments", len(args))}
+	}
+
+	return selector, nil
+}
+
+// isSingleRun reports whether the run was given as arguments
+func (s runSelector) isSingleRun() bool {
+	return s.repo != ""
+}
+
+// match reports whether a scanned run matches the selector flags and the configured repository
+func (s runSelector) match(job scanner.JobStatus, cfg *config.Config) bool {
+	if cfg.GitHub.Repo != "" && job.Repository != cfg.GitHub.Repo {
+		return false
+	}
+	if s.branch != "" && job.Branch != s.branch {
+		return false
+	}
+	if s.environment != "" {
+		for _, environment := range strings.Split(job.Environment, ", ") {
+			if strings.EqualFold(environment, s.environment) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// describeRun formats a run for the confirmation plan and results
+func describeRun(job scanner.JobStatus) string {
+	return fmt.Sprintf("%s #%d %s (%s)", job.Repository, job.RunNumber, job.WorkflowName, job.Branch)
+}
+
+// confirm asks for confirmation on the terminal. Without a terminal --yes is required.
+func confirm(cmd *cobra.Command, prompt string) error {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return nil
+	}
+	if !isTerminal() {
+		return &exitError{code: exitCodeConfig, err: fmt.Errorf("stdin is not a terminal, pass --yes to confirm")}
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return fmt.Errorf("aborted")
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/younsl/cocd/pkg/metrics"
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/server"
+)
+
+// shutdownTimeout bounds how long serve waits for in-flight requests on exit
+const shutdownTimeout = 5 * time.Second
+
+// webhookReconcileInterval is the default scan interval with --webhook, when
+// scans only catch up on deliveries that were missed
+const webhookReconcileInterval = 300
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the monitor headless and export Prometheus metrics and a JSON API",
+	Long: `serve scans the organization on every interval without a terminal and exposes
+the results as Prometheus metrics on /metrics, for example to alert when a
+production approval has been waiting for too long:
+
+  max by (repository) (cocd_oldest_waiting_run_age_seconds{environment="production"}) > 1800
+
+With --listen it also serves a read-only JSON API backed by the same scans, so
+a team can share one scanner instead of each running its own:
+
+  GET /api/v1/jobs/pending   approval waiting jobs, optionally ?filter=<query>
+  GET /api/v1/jobs/recent    recent jobs in any status, optionally ?filter=<query>
+  GET /api/v1/progress       progress of the current scan
+  GET /api/v1/events         server-sent events for run state changes
+
+Every endpoint requires "Authorization: Bearer <token>" with the GitHub token of
+a member of the organization. Serve it behind TLS.
+
+It also forwards the few GitHub API calls the TUI needs to review and cancel
+runs, with the caller's own token, so that "cocd --server" can be used by
+engineers who do not run a scanner themselves.
+
+With --webhook it also receives GitHub webhook deliveries on /webhook, signed
+with webhook.secret, and updates the runs of workflow_run, workflow_job,
+deployment_review and deployment_protection_rule events right away. Scans then
+run every 5 minutes by default, only to reconcile deliveries that were missed.
+
+--listen may be the same address as --metrics-addr to serve both on one port.`,
+	Example: `  cocd serve --org my-org --metrics-addr :9090
+  cocd serve --org my-org --listen :8080 --interval 60
+  COCD_WEBHOOK_SECRET=... cocd serve --org my-org --listen :8080 --webhook`,
+	Args: cobra.NoArgs,
+	// main prints errors and picks the exit code
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runServe,
+}
+
+func init() {
+	serveCmd.Flags().String("metrics-addr", ":9090", "Address to serve Prometheus metrics on, empty to disable")
+	serveCmd.Flags().String("listen", "", "Address to serve the JSON API on (default disabled)")
+	serveCmd.Flags().IntP("interval", "i", 0, "Scan interval in seconds (default monitor.interval, 300 with --webhook)")
+	serveCmd.Flags().Bool("webhook", false, "Receive GitHub webhook deliveries on /webhook of the --listen address")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	metricsAddr, _ := cmd.Flags().GetString("metrics-addr")
+	listenAddr, _ := cmd.Flags().GetString("listen")
+	webhook, _ := cmd.Flags().GetBool("webhook")
+	if metricsAddr == "" && listenAddr == "" {
+		return &exitError{code: exitCodeConfig, err: fmt.Errorf("nothing to serve, set --metrics-addr or --listen")}
+	}
+	if webhook && listenAddr == "" {
+		return &exitError{code: exitCodeConfig, err: fmt.Errorf("--webhook requires --listen")}
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if webhook && cfg.Webhook.Secret == "" {
+		return &exitError{code: exitCodeConfig, err: fmt.Errorf("--webhook requires webhook.secret or COCD_WEBHOOK_SECRET")}
+	}
+	if interval, _ := cmd.Flags().GetInt("interval"); interval > 0 {
+		cfg.Monitor.Interval = interval
+	} else if webhook {
+		cfg.Monitor.Interval = webhookReconcileInterval
+	}
+
+	mon, err := newMonitor(cfg)
+	if err != nil {
+		return err
+	}
+	defer mon.GetClient().Close()
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Every scan result goes to all enabled consumers
+	var consumers []func(monitor.ScanResult)
+	muxes := make(map[string]*http.ServeMux)
+	route := func(addr, pattern string, handler http.Handler) {
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+			muxes[addr].HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+		}
+		muxes[addr].Handle(pattern, handler)
+	}
+
+	var exporter *metrics.Exporter
+	if metricsAddr != "" {
+		exporter = metrics.NewExporter(mon)
+		consumers = append(consumers, exporter.ObserveScan)
+		route(metricsAddr, "/metrics", exporter.Handler())
+	}
+	if listenAddr != "" {
+		api, err := server.New(mon, cfg.GitHub.BaseURL)
+		if err != nil {
+			return &exitError{code: exitCodeConfig, err: err}
+		}
+		// The API merges the scans with the webhook updates, so the run gauges
+		// follow its jobs. It consumes the scans after the exporter to have the last word.
+		consumers = append(consumers, api.Update)
+		if exporter != nil {
+			api.OnJobsChanged(exporter.Observe)
+		}
+		route(listenAddr, "/api/", api.Handler())
+		if webhook {
+			route(listenAddr, "/webhook", api.WebhookHandler(cfg.Webhook.Secret))
+		}
+	}
+
+	serverErr := make(chan error, len(muxes))
+	var servers []*http.Server
+	for addr, mux := range muxes {
+		srv := &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+			// Ends open event streams on shutdown instead of waiting for the clients
+			BaseContext: func(net.Listener) context.Context { return ctx },
+		}
+		servers = append(servers, srv)
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- fmt.Errorf("failed to serve on %s: %w", srv.Addr, err)
+			}
+		}()
+	}
+	if metricsAddr != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Serving metrics on %s/metrics\n", metricsAddr)
+	}
+	if listenAddr != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Serving the JSON API on %s/api/v1\n", listenAddr)
+	}
+	if webhook {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Receiving GitHub webhooks on %s/webhook, reconciling every %ds\n", listenAddr, cfg.Monitor.Interval)
+	}
+
+	go mon.StartScanning(ctx, func(result monitor.ScanResult) {
+		for _, consume := range consumers {
+			consume(result)
+		}
+	})
+
+	select {
+	case err = <-serverErr:
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		srv.Shutdown(shutdownCtx)
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream workflow run state changes as JSON lines",
+	Long: `watch scans the organization continuously and prints one JSON object per line
+whenever a run changes state, instead of full snapshots:
+
+  waiting    the run is waiting for a deployment approval
+  approved   the run left the waiting state and continued
+  started    the run started running
+  completed  the run finished without failing
+  cancelled  the run was cancelled
+  failed     the run failed, timed out or was rejected
+
+Runs that are already waiting when watch starts are reported once. watch runs
+until it is interrupted.`,
+	Example: `  cocd watch --org my-org
+  cocd watch --org my-org --events waiting,approved | jq -r '.job.repository'
+  cocd watch --org my-org --filter "branch:main" >> /var/log/cocd/events.ndjson`,
+	Args: cobra.NoArgs,
+	// main prints errors and picks the exit code
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runWatch,
+}
+
+func init() {
+	watchCmd.Flags().IntP("interval", "i", 0, "Scan interval in seconds (default monitor.interval)")
+	watchCmd.Flags().String("filter", "", "Only report runs matching a filter query, e.g. \"branch:main actor:/^bot-/\"")
+	watchCmd.Flags().StringSlice("events", nil, "Only report these event types (default all)")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	query, _ := cmd.Flags().GetString("filter")
+	filter, err := scanner.ParseFilter(query)
+	if err != nil {
+		return &exitError{code: exitCodeConfig, err: fmt.Errorf("invalid filter: %w", err)}
+	}
+
+	names, _ := cmd.Flags().GetStringSlice("events")
+	eventTypes, err := parseEventTypes(names)
+	if err != nil {
+		return &exitError{code: exitCodeConfig, err: err}
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if interval, _ := cmd.Flags().GetInt("interval"); interval > 0 {
+		cfg.Monitor.Interval = interval
+	}
+
+	mon, err := newMonitor(cfg)
+	if err != nil {
+		return err
+	}
+	defer mon.GetClient().Close()
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	differ := monitor.NewEventDiffer(monitor.DefaultEventRetention)
+	emit := func(jobs []scanner.JobStatus) error {
+		for _, event := range differ.Diff(filter.Apply(jobs), time.Now().UTC()) {
+			if eventTypes != nil && !eventTypes[event.Type] {
+				continue
+			}
+			event.URL = event.Job.GetActionsURL(cfg.GitHub.BaseURL, cfg.GitHub.Org)
+			if err := encoder.Encode(event); err != nil {
+				return fmt.Errorf("failed to write event: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// The monitor only scans after the first interval, so take the baseline right away
+	jobs, err := mon.GetRecentJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to scan jobs: %w", err)
+	}
+	if err := emit(jobs); err != nil {
+		return err
+	}
+
+	jobChan := make(chan []scanner.JobStatus)
+	go mon.StartMonitoringRecent(ctx, jobChan)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case jobs := <-jobChan:
+			if err := emit(jobs); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseEventTypes validates --events. No names selects every event type.
+func parseEventTypes(names []string) (map[monitor.EventType]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	selected := make(map[monitor.EventType]bool, len(names))
+	for _, name := range names {
+		found := false
+		for _, eventType := range monitor.EventTypes {
+			if string(eventType) == strings.ToLower(strings.TrimSpace(name)) {
+				selected[eventType] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown event type %q", name)
+		}
+	}
+	return selected, nil
+}
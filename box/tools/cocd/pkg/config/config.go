@@ -8,11 +8,16 @@ import (
 	"strings"
 
 	"github.com/spf13/viper"
+	"github.com/younsl/cocd/pkg/workflow"
 )
 
 type Config struct {
 	GitHub GitHubConfig `mapstructure:"github"`
 	Monitor MonitorConfig `mapstructure:"monitor"`
+	Approval ApprovalConfig `mapstructure:"approval"`
+	Remote RemoteConfig `mapstructure:"remote"`
+	Webhook WebhookConfig `mapstructure:"webhook"`
+	History HistoryConfig `mapstructure:"history"`
 }
 
 type GitHubConfig struct {
@@ -20,11 +25,64 @@ type GitHubConfig struct {
 	BaseURL  string `mapstructure:"base_url"`
 	Org      string `mapstructure:"org"`
 	Repo     string `mapstructure:"repo"`
+	// TokenFile and TokenCommand read the token from a file or from a command's
+	// output, such as a password manager, instead of keeping it in the config file
+	TokenFile    string `mapstructure:"token_file"`
+	TokenCommand string `mapstructure:"token_command"`
+	// CacheFile keeps the ETag cache of API responses between runs, in memory only when empty
+	CacheFile string `mapstructure:"cache_file"`
+	// App authenticates as a GitHub App installation instead of with a token
+	App GitHubAppConfig `mapstructure:"app"`
+
+	tokenSource string // Where Load found Token, see ReadToken
+}
+
+type GitHubAppConfig struct {
+	ID             int64  `mapstructure:"id"`
+	InstallationID int64  `mapstructure:"installation_id"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+}
+
+// Enabled reports whether GitHub App authentication is configured
+func (a GitHubAppConfig) Enabled() bool {
+	return a.ID != 0 || a.InstallationID != 0 || a.PrivateKeyFile != ""
 }
 
 type MonitorConfig struct {
 	Interval int `mapstructure:"interval"`
 	Timezone string `mapstructure:"timezone"`
+	// Workers is how many repositories are scanned concurrently
+	Workers int `mapstructure:"workers"`
+	// RequestsPerSecond caps the GitHub API requests of all workers together, zero is unlimited
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	// ScanStrategy reads the workflow runs with the REST ("rest") or the GraphQL ("graphql") API
+	ScanStrategy string `mapstructure:"scan_strategy"`
+}
+
+type ApprovalConfig struct {
+	// CommentTemplate supports {comment}, {action}, {user}, {repo}, {workflow},
+	// {branch}, {run_number}, {run_id} and {timestamp} placeholders
+	CommentTemplate string `mapstructure:"comment_template"`
+}
+
+type RemoteConfig struct {
+	// ServerURL points the TUI at a cocd server started with "cocd serve --listen"
+	// instead of scanning GitHub itself
+	ServerURL string `mapstructure:"server_url"`
+}
+
+type WebhookConfig struct {
+	// Secret verifies the signature of the deliveries received by 'cocd serve --webhook'
+	Secret string `mapstructure:"secret"`
+}
+
+type HistoryConfig struct {
+	// Enabled keeps the runs seen by the TUI, their status changes and reviews on disk
+	Enabled bool `mapstructure:"enabled"`
+	// File is the history store, in the XDG data directory by default
+	File string `mapstructure:"file"`
+	// RetentionDays drops runs not seen for that many days, zero keeps them forever
+	RetentionDays int `mapstructure:"retention_days"`
 }
 
 func Load() (*Config, error) {
@@ -53,8 +111,23 @@ func Load() (*Config, error) {
 	viper.AutomaticEnv()
 
 	viper.SetDefault("github.base_url", "api.github.com")
+	viper.SetDefault("github.token_file", "")
+	viper.SetDefault("github.token_command", "")
+	viper.SetDefault("github.cache_file", "")
+	viper.SetDefault("github.app.id", 0)
+	viper.SetDefault("github.app.installation_id", 0)
+	viper.SetDefault("github.app.private_key_file", "")
 	viper.SetDefault("monitor.interval", 5)
 	viper.SetDefault("monitor.timezone", "UTC")
+	viper.SetDefault("monitor.workers", 4)
+	viper.SetDefault("monitor.requests_per_second", 10)
+	viper.SetDefault("monitor.scan_strategy", "rest")
+	viper.SetDefault("approval.comment_template", workflow.DefaultCommentTemplate)
+	viper.SetDefault("remote.server_url", "")
+	viper.SetDefault("webhook.secret", "")
+	viper.SetDefault("history.enabled", true)
+	viper.SetDefault("history.file", "")
+	viper.SetDefault("history.retention_days", 30)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -72,19 +145,56 @@ func Load() (*Config, error) {
 		config.GitHub.BaseURL = "https://" + config.GitHub.BaseURL
 	}
 
-	if config.GitHub.Token == "" {
-		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-			config.GitHub.Token = token
-		} else if token := getGHToken(); token != "" {
-			config.GitHub.Token = token
-		} else {
-			return nil, fmt.Errorf("GitHub token is required. Please set GITHUB_TOKEN environment variable or login with 'gh auth login'")
+	config.GitHub.TokenFile = expandHome(config.GitHub.TokenFile)
+	config.GitHub.CacheFile = expandHome(config.GitHub.CacheFile)
+	config.GitHub.App.PrivateKeyFile = expandHome(config.GitHub.App.PrivateKeyFile)
+	config.History.File = expandHome(config.History.File)
+	if config.History.File == "" {
+		config.History.File = filepath.Join(GetDataDir(), "history.db")
+	}
+
+	if config.Monitor.Workers < 1 {
+		return nil, fmt.Errorf("monitor.workers must be at least 1")
+	}
+	if config.Monitor.RequestsPerSecond < 0 {
+		return nil, fmt.Errorf("monitor.requests_per_second must not be negative")
+	}
+	if config.Monitor.ScanStrategy != "rest" && config.Monitor.ScanStrategy != "graphql" {
+		return nil, fmt.Errorf("monitor.scan_strategy must be rest or graphql, got %q", config.Monitor.ScanStrategy)
+	}
+	if config.History.RetentionDays < 0 {
+		return nil, fmt.Errorf("history.retention_days must not be negative")
+	}
+
+	if config.GitHub.App.Enabled() {
+		app := config.GitHub.App
+		if app.ID == 0 || app.InstallationID == 0 || app.PrivateKeyFile == "" {
+			return nil, fmt.Errorf("GitHub App authentication requires github.app.id, github.app.installation_id and github.app.private_key_file")
 		}
+		// The app's installation tokens are used instead of a personal token
+		return &config, nil
+	}
+
+	warnAboutPlaintextToken(config.GitHub.Token)
+	if err := config.GitHub.resolveToken(); err != nil {
+		return nil, err
 	}
 
 	return &config, nil
 }
 
+// expandHome replaces a leading ~/ with the home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
 func getGHToken() string {
 	cmd := exec.Command("gh", "auth", "token")
 	output, err := cmd.Output()
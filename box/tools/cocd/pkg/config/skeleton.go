@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/younsl/cocd/pkg/workflow"
 	"gopkg.in/yaml.v3"
 )
 
@@ -12,18 +13,53 @@ import (
 type ConfigSkeleton struct {
 	GitHub  GitHubSkeleton  `yaml:"github"`
 	Monitor MonitorSkeleton `yaml:"monitor"`
+	Approval ApprovalSkeleton `yaml:"approval"`
+	Remote RemoteSkeleton `yaml:"remote"`
+	Webhook WebhookSkeleton `yaml:"webhook"`
+	History HistorySkeleton `yaml:"history"`
 }
 
 type GitHubSkeleton struct {
 	Token   string `yaml:"token" comment:"GitHub token (can also be set via COCD_GITHUB_TOKEN or GITHUB_TOKEN env var)\nYou can also authenticate using 'gh auth login' command"`
+	TokenFile    string `yaml:"token_file" comment:"File that holds the GitHub token (optional)"`
+	TokenCommand string `yaml:"token_command" comment:"Command that prints the GitHub token (optional)"`
 	BaseURL string `yaml:"base_url" comment:"GitHub API base URL\nFor GitHub Enterprise Server, use: https://github.example.com/api/v3"`
 	Org     string `yaml:"org" comment:"GitHub organization name (required)\nCan also be set via COCD_GITHUB_ORG env var"`
 	Repo    string `yaml:"repo" comment:"GitHub repository name (optional)\nIf not specified, monitors all repositories in the organization\nCan also be set via COCD_GITHUB_REPO env var"`
+	CacheFile string `yaml:"cache_file" comment:"File that keeps the API response cache between runs (optional)"`
+	App GitHubAppSkeleton `yaml:"app"`
+}
+
+type GitHubAppSkeleton struct {
+	ID             int64  `yaml:"id"`
+	InstallationID int64  `yaml:"installation_id"`
+	PrivateKeyFile string `yaml:"private_key_file"`
 }
 
 type MonitorSkeleton struct {
 	Interval    int    `yaml:"interval" comment:"Refresh interval in seconds"`
 	Timezone    string `yaml:"timezone" comment:"Timezone for displaying timestamps\nExamples: UTC, Asia/Seoul, America/New_York, Europe/London, Asia/Tokyo"`
+	Workers     int    `yaml:"workers" comment:"Repositories scanned concurrently"`
+	RequestsPerSecond float64 `yaml:"requests_per_second" comment:"GitHub API requests per second of all workers together"`
+	ScanStrategy string `yaml:"scan_strategy" comment:"API used to read workflow runs: rest or graphql"`
+}
+
+type ApprovalSkeleton struct {
+	CommentTemplate string `yaml:"comment_template" comment:"Review comment template for approvals and rejections"`
+}
+
+type RemoteSkeleton struct {
+	ServerURL string `yaml:"server_url" comment:"URL of a shared cocd server to use instead of scanning GitHub"`
+}
+
+type WebhookSkeleton struct {
+	Secret string `yaml:"secret" comment:"Secret of the GitHub webhook that sends deliveries to 'cocd serve --webhook'"`
+}
+
+type HistorySkeleton struct {
+	Enabled       bool   `yaml:"enabled" comment:"Keep a local history of the runs seen by the TUI"`
+	File          string `yaml:"file" comment:"History file (default: $XDG_DATA_HOME/cocd/history.db)"`
+	RetentionDays int    `yaml:"retention_days" comment:"Days a run is kept after it was last seen"`
 }
 
 func GetDefaultConfig() *ConfigSkeleton {
@@ -37,6 +73,16 @@ func GetDefaultConfig() *ConfigSkeleton {
 		Monitor: MonitorSkeleton{
 			Interval:    5,
 			Timezone:    "UTC",
+			Workers:     4,
+			RequestsPerSecond: 10,
+			ScanStrategy: "rest",
+		},
+		Approval: ApprovalSkeleton{
+			CommentTemplate: workflow.DefaultCommentTemplate,
+		},
+		History: HistorySkeleton{
+			Enabled:       true,
+			RetentionDays: 30,
 		},
 	}
 }
@@ -53,6 +99,16 @@ func GetConfigDir() string {
 	return filepath.Join(homeDir, ".config", "cocd")
 }
 
+// GetDataDir returns the directory of the files cocd keeps between runs, following XDG Base Directory specification
+func GetDataDir() string {
+	if xdgData := os.Getenv("XDG_DATA_HOME"); xdgData != "" {
+		return filepath.Join(xdgData, "cocd")
+	}
+	
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".local", "share", "cocd")
+}
+
 func GetConfigPaths() []string {
 	configDir := GetConfigDir()
 	homeDir, _ := os.UserHomeDir()
@@ -126,18 +182,52 @@ func addComments(node *yaml.Node) {
 				key.HeadComment = "GitHub configuration"
 			case "token":
 				key.HeadComment = "GitHub token (can also be set via COCD_GITHUB_TOKEN or GITHUB_TOKEN env var)\nYou can also authenticate using 'gh auth login' command"
+			case "token_file":
+				key.HeadComment = "File that holds the GitHub token, read again when GitHub rejects the token (optional)\nExample: ~/.config/cocd/token"
+			case "token_command":
+				key.HeadComment = "Command that prints the GitHub token, run again when GitHub rejects the token (optional)\nExamples: pass show github/cocd, op read op://Private/GitHub/token, vault kv get -field=token secret/cocd"
 			case "base_url":
 				key.HeadComment = "GitHub API base URL (default: api.github.com)\nFor GitHub Enterprise Server, use: github.example.com/api/v3"
 			case "org":
 				key.HeadComment = "GitHub organization name (required)\nCan also be set via COCD_GITHUB_ORG env var"
 			case "repo":
 				key.HeadComment = "GitHub repository name (optional)\nIf not specified, monitors all repositories in the organization\nCan also be set via COCD_GITHUB_REPO env var"
+			case "cache_file":
+				key.HeadComment = "File that keeps the ETag cache of API responses between runs (optional)\nUnchanged responses are then revalidated instead of downloaded again, even right after a restart\nExample: ~/.cache/cocd/responses.json"
+			case "app":
+				key.HeadComment = "GitHub App authentication (optional), used instead of the token when set\nRequires the app ID, the installation ID in the organization and the app's private key"
 			case "monitor":
 				key.HeadComment = "\nMonitor configuration"
 			case "interval":
 				key.HeadComment = "Refresh interval in seconds (default: 5)"
 			case "timezone":
 				key.HeadComment = "Timezone for displaying timestamps (default: UTC)\nExamples: UTC, Asia/Seoul, America/New_York, Europe/London, Asia/Tokyo"
+			case "workers":
+				key.HeadComment = "Repositories scanned concurrently (default: 4)"
+			case "requests_per_second":
+				key.HeadComment = "GitHub API requests per second of all workers together (default: 10, 0 for no limit)\nLower it to reduce the load on a GitHub Enterprise Server"
+			case "scan_strategy":
+				key.HeadComment = "API used to read workflow runs (default: rest)\nrest: one request per repository, sees every recent run\ngraphql: one query per 25 repositories, sees the runs on the latest commit of the 10 most recent branches"
+			case "approval":
+				key.HeadComment = "\nApproval configuration"
+			case "remote":
+				key.HeadComment = "\nRemote configuration"
+			case "webhook":
+				key.HeadComment = "\nWebhook configuration"
+			case "history":
+				key.HeadComment = "\nHistory configuration"
+			case "enabled":
+				key.HeadComment = "Keep the runs seen by the TUI, their status changes and approvals on disk (default: true)\nThe TUI shows them right at startup, before the first scan finishes"
+			case "file":
+				key.HeadComment = "History file (default: $XDG_DATA_HOME/cocd/history.db, or ~/.local/share/cocd/history.db)\nOnly one TUI can use it at a time"
+			case "retention_days":
+				key.HeadComment = "Days a run is kept after it was last seen (default: 30, 0 keeps runs forever)"
+			case "secret":
+				key.HeadComment = "Secret of the GitHub webhook that sends deliveries to 'cocd serve --webhook' (optional)\nCan also be set via COCD_WEBHOOK_SECRET env var"
+			case "server_url":
+				key.HeadComment = "URL of a shared cocd server started with 'cocd serve --listen' (optional)\nIf set, the TUI shows the server's scans instead of scanning GitHub itself\nCan also be set via COCD_REMOTE_SERVER_URL env var or the --server flag"
+			case "comment_template":
+				key.HeadComment = "Review comment template for approvals and rejections\nPlaceholders: {comment}, {action}, {user}, {repo}, {workflow}, {branch}, {run_number}, {run_id}, {timestamp}\nIf {comment} is omitted, the comment typed in the TUI is prepended to the message"
 			}
 			
 			if value.Kind == yaml.MappingNode {
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// tokenCommandTimeout bounds token_command, which may wait for an unlocked password store
+const tokenCommandTimeout = 30 * time.Second
+
+// Where the GitHub token was found
+const (
+	tokenSourceStatic  = "static"
+	tokenSourceFile    = "file"
+	tokenSourceCommand = "command"
+	tokenSourceGH      = "gh"
+)
+
+// resolveToken finds the GitHub token in the configured sources, in order:
+// github.token, token_file, token_command, GITHUB_TOKEN and finally 'gh auth token'
+func (g *GitHubConfig) resolveToken() error {
+	switch {
+	case g.Token != "":
+		g.tokenSource = tokenSourceStatic
+		return nil
+	case g.TokenFile != "":
+		g.tokenSource = tokenSourceFile
+		warnIfReadableByOthers(g.TokenFile, "Token file")
+	case g.TokenCommand != "":
+		g.tokenSource = tokenSourceCommand
+	case os.Getenv("GITHUB_TOKEN") != "":
+		g.Token = os.Getenv("GITHUB_TOKEN")
+		g.tokenSource = tokenSourceStatic
+		return nil
+	default:
+		g.tokenSource = tokenSourceGH
+	}
+
+	token, err := g.ReadToken()
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("GitHub token is required. Please set GITHUB_TOKEN environment variable, configure github.token_command or github.token_file, or login with 'gh auth login'")
+	}
+	g.Token = token
+	return nil
+}
+
+// UserToken returns the personal GitHub token of the user. Load skips it when a
+// GitHub App is configured, but remote mode reviews and cancels runs as the
+// user and needs it all the same.
+func (g *GitHubConfig) UserToken() (string, error) {
+	if g.tokenSource == "" {
+		warnAboutPlaintextToken(g.Token)
+		if err := g.resolveToken(); err != nil {
+			return "", err
+		}
+	}
+	return g.Token, nil
+}
+
+// ReadToken reads the GitHub token again from the source Load found it in.
+// Tokens kept in a file, a password manager or the GitHub CLI can be rotated
+// while cocd runs; tokens given directly are returned unchanged.
+func (g *GitHubConfig) ReadToken() (string, error) {
+	switch g.tokenSource {
+	case tokenSourceFile:
+		data, err := os.ReadFile(g.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", g.TokenFile)
+		}
+		return token, nil
+	case tokenSourceCommand:
+		return runTokenCommand(g.TokenCommand)
+	case tokenSourceGH:
+		return getGHToken(), nil
+	default:
+		return g.Token, nil
+	}
+}
+
+// SetToken replaces the token with one that is not read again, such as the --token flag
+func (g *GitHubConfig) SetToken(token string) {
+	g.Token = token
+	g.tokenSource = tokenSourceStatic
+}
+
+// runTokenCommand runs token_command with the shell and returns its output
+func runTokenCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("token_command failed: %w: %s", err, message)
+		}
+		return "", fmt.Errorf("token_command failed: %w", err)
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", fmt.Errorf("token_command printed no token")
+	}
+	return token, nil
+}
+
+// warnAboutPlaintextToken warns when the config file holds the token itself
+// and other users may read it
+func warnAboutPlaintextToken(token string) {
+	configFile := viper.ConfigFileUsed()
+	if token == "" || configFile == "" || !viper.InConfig("github.token") || os.Getenv("COCD_GITHUB_TOKEN") != "" {
+		return
+	}
+	warnIfReadableByOthers(configFile, "Config file")
+}
+
+// warnIfReadableByOthers warns when group or other users have any access to a file
+func warnIfReadableByOthers(path, what string) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0077 == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s %s holds a GitHub token but is readable by other users (mode %04o), restrict it with 'chmod 600 %s'\n", what, path, info.Mode().Perm(), path)
+}
@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// appJWTLifetime stays under the ten minutes GitHub accepts
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew backdates the JWT in case the local clock runs ahead of GitHub's
+	appJWTClockSkew = time.Minute
+	// installationTokenRefresh renews installation tokens this long before they expire
+	installationTokenRefresh = 5 * time.Minute
+	// appRequestTimeout bounds the requests made with the app's JWT
+	appRequestTimeout = 30 * time.Second
+)
+
+// AppCredentials authenticate a client as an installation of a GitHub App
+// instead of with a personal access token
+type AppCredentials struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKeyFile string // PEM encoded private key downloaded from the app settings
+}
+
+// appAuth creates installation tokens for a GitHub App. Tokens are signed
+// requests with a short-lived JWT and expire after an hour.
+type appAuth struct {
+	credentials AppCredentials
+	key         *rsa.PrivateKey
+	apiURL      *url.URL
+	http        *http.Client
+
+	mu   sync.Mutex
+	slug string
+}
+
+// newAppAuth loads the private key of an app. apiURL is the REST API root,
+// such as https://api.github.com/ or https://github.example.com/api/v3/.
+func newAppAuth(credentials AppCredentials, apiURL *url.URL) (*appAuth, error) {
+	if credentials.AppID == 0 || credentials.InstallationID == 0 || credentials.PrivateKeyFile == "" {
+		return nil, fmt.Errorf("GitHub App authentication requires an app ID, an installation ID and a private key file")
+	}
+
+	data, err := os.ReadFile(credentials.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+	key, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App private key %s: %w", credentials.PrivateKeyFile, err)
+	}
+
+	return &appAuth{
+		credentials: credentials,
+		key:         key,
+		apiURL:      apiURL,
+		http:        &http.Client{Timeout: appRequestTimeout},
+	}, nil
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	// GitHub hands out PKCS#1 keys, converted keys are often PKCS#8
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA key")
+	}
+	return key, nil
+}
+
+// tokenSource returns installation tokens, renewed shortly before they expire
+func (a *appAuth) tokenSource() oauth2.TokenSource {
+	return oauth2.ReuseTokenSourceWithExpiry(nil, a, installationTokenRefresh)
+}
+
+// Token creates a new installation access token
+func (a *appAuth) Token() (*oauth2.Token, error) {
+	var response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	path := fmt.Sprintf("app/installations/%d/access_tokens", a.credentials.InstallationID)
+	if err := a.do(context.Background(), http.MethodPost, path, http.StatusCreated, &response); err != nil {
+		return nil, fmt.Errorf("failed to create GitHub App installation token: %w", err)
+	}
+
+	return &oauth2.Token{AccessToken: response.Token, Expiry: response.ExpiresAt}, nil
+}
+
+// login returns the bot account the installation acts as, such as "my-app[bot]".
+// Installation tokens cannot read /user, so it is derived from the app's slug.
+func (a *appAuth) login(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.slug == "" {
+		var app struct {
+			Slug string `json:"slug"`
+		}
+		if err := a.do(ctx, http.MethodGet, "app", http.StatusOK, &app); err != nil {
+			return "", fmt.Errorf("failed to get GitHub App: %w", err)
+		}
+		a.slug = app.Slug
+	}
+	return a.slug + "[bot]", nil
+}
+
+// do sends a request authenticated as the app itself and decodes the response
+func (a *appAuth) do(ctx context.Context, method, path string, wantStatus int, v interface{}) error {
+	jwt, err := a.signJWT(time.Now())
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, a.apiURL.JoinPath(path).String(), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("Authorization", "Bearer "+jwt)
+
+	response, err := a.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != wantStatus {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 64*1024))
+		if json.Unmarshal(body, &failure) == nil && failure.Message != "" {
+			return fmt.Errorf("%s: %s", response.Status, failure.Message)
+		}
+		return fmt.Errorf("%s", response.Status)
+	}
+
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+// signJWT creates the RS256 JSON Web Token that authenticates as the app
+func (a *appAuth) signJWT(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(a.credentials.AppID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,244 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCacheBytes bounds the response bodies kept by the cache, least recently used go first
+	maxCacheBytes = 64 << 20
+	// cacheSaveInterval is how often a changed cache is written to its file
+	cacheSaveInterval = time.Minute
+)
+
+// cacheEntry is a response kept for revalidation with If-None-Match
+type cacheEntry struct {
+	ETag     string      `json:"etag"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	LastUsed time.Time   `json:"last_used"`
+}
+
+// responseCache keeps GET responses that carry an ETag, keyed by URL, and
+// optionally persists them to a file between sessions
+type responseCache struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	size    int
+	hits    uint64
+	dirty   bool
+	savedAt time.Time
+	saving  bool
+}
+
+// newResponseCache creates a cache, loading the entries saved in path if it is set.
+// A missing or unreadable file starts an empty cache.
+func newResponseCache(path string) *responseCache {
+	c := &responseCache{
+		path:    path,
+		entries: make(map[string]*cacheEntry),
+		savedAt: time.Now(),
+	}
+	if path == "" {
+		return c
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var entries map[string]*cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return c
+	}
+	for key, entry := range entries {
+		c.entries[key] = entry
+		c.size += len(entry.Body)
+	}
+	c.evict()
+	return c
+}
+
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok {
+		entry.LastUsed = time.Now()
+	}
+	return entry, ok
+}
+
+func (c *responseCache) hit() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hits++
+}
+
+func (c *responseCache) hitCount() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+func (c *responseCache) put(key string, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if previous, ok := c.entries[key]; ok {
+		c.size -= len(previous.Body)
+	}
+	c.entries[key] = entry
+	c.size += len(entry.Body)
+	c.dirty = true
+	c.evict()
+
+	if c.path != "" && !c.saving && time.Since(c.savedAt) > cacheSaveInterval {
+		c.saving = true
+		go c.save()
+	}
+}
+
+// evict drops the least recently used entries until the cache fits maxCacheBytes
+func (c *responseCache) evict() {
+	for c.size > maxCacheBytes {
+		var oldestKey string
+		var oldest *cacheEntry
+		for key, entry := range c.entries {
+			if oldest == nil || entry.LastUsed.Before(oldest.LastUsed) {
+				oldestKey, oldest = key, entry
+			}
+		}
+		c.size -= len(oldest.Body)
+		delete(c.entries, oldestKey)
+	}
+}
+
+// save writes the cache to its file if it changed since the last save
+func (c *responseCache) save() error {
+	c.mu.Lock()
+	c.saving = false
+	if c.path == "" || !c.dirty {
+		c.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(c.entries)
+	c.dirty = false
+	c.savedAt = time.Now()
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode response cache: %w", err)
+	}
+
+	// Responses can hold private repository data, so only the user may read them
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write response cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write response cache: %w", err)
+	}
+	return nil
+}
+
+// cacheTransport makes GET requests conditional on the ETag of the cached
+// response and answers 304 Not Modified from the cache. On github.com those
+// revalidations do not count against the rate limit.
+type cacheTransport struct {
+	base  http.RoundTripper
+	cache *responseCache
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	entry, cached := t.cache.get(key)
+	if cached {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		t.cache.hit()
+		return cachedResponse(req, resp, entry), nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.cache.put(key, &cacheEntry{
+		ETag:     etag,
+		Header:   resp.Header.Clone(),
+		Body:     body,
+		LastUsed: time.Now(),
+	})
+	return resp, nil
+}
+
+// cachedResponse turns a 304 into the cached 200 response. The headers of the
+// 304, such as the current rate limit, replace the cached ones.
+func cachedResponse(req *http.Request, notModified *http.Response, entry *cacheEntry) *http.Response {
+	io.Copy(io.Discard, notModified.Body)
+	notModified.Body.Close()
+
+	header := entry.Header.Clone()
+	for name, values := range notModified.Header {
+		header[name] = values
+	}
+	header.Set("Content-Length", strconv.Itoa(len(entry.Body)))
+	// Marks the response as cached like httpcache does, so go-github leaves its rate limit alone
+	header.Set("X-From-Cache", "1")
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+// Close writes the response cache to its file, if one is configured. Clients
+// created by WithOrganization share the cache, so closing one is enough.
+func (c *Client) Close() error {
+	if c.cache == nil {
+		return nil
+	}
+	return c.cache.save()
+}
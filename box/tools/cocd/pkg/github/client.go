@@ -1,54 +1,146 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"reflect"
+	"time"
 
 	"github.com/google/go-github/v60/github"
 	"golang.org/x/oauth2"
 )
 
+const (
+	// maxJobLogBytes is how much of a job log is kept, the end of it when the log is longer
+	maxJobLogBytes = 8 << 20
+	// maxJobLogDownload bounds the bytes read from a log download while looking for its end
+	maxJobLogDownload = 256 << 20
+	// jobLogTimeout bounds a log download, including reading its body
+	jobLogTimeout = 2 * time.Minute
+)
+
+// logClient downloads job logs from the storage URLs the API redirects to
+var logClient = &http.Client{Timeout: jobLogTimeout}
+
 type Client struct {
-	client *github.Client
-	org    string
-	repo   string
+	client  *github.Client
+	org     string
+	repo    string
+	stats   *statsTransport
+	limiter *rateLimiter
+	cache   *responseCache
+	app     *appAuth // Set when authenticated as a GitHub App installation
+}
+
+// ClientOptions configures a client created by NewClientWithOptions
+type ClientOptions struct {
+	Token   string
+	BaseURL string
+	Org     string
+	Repo    string // Optional, limits the client to one repository
+	// TokenRefresh reads Token again when GitHub rejects it, for tokens that are rotated
+	TokenRefresh func() (string, error)
+	// RequestsPerSecond caps the requests sent to the API across all goroutines
+	// using the client. Zero leaves them unlimited.
+	RequestsPerSecond float64
+	// App authenticates as a GitHub App installation instead of with Token
+	App *AppCredentials
+	// CacheFile keeps the ETag response cache between sessions. Empty keeps it in memory only.
+	CacheFile string
 }
 
 func NewClient(token, baseURL, org string, repo ...string) (*Client, error) {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+	var repoName string
+	if len(repo) > 0 {
+		repoName = repo[0]
+	}
 
-	client := github.NewClient(tc)
+	return NewClientWithOptions(ClientOptions{
+		Token:   token,
+		BaseURL: baseURL,
+		Org:     org,
+		Repo:    repoName,
+	})
+}
 
-	if baseURL != "" && baseURL != "https://api.github.com" {
-		// Ensure trailing slash for GitHub API
-		if baseURL[len(baseURL)-1] != '/' {
-			baseURL += "/"
-		}
-		parsedURL, err := url.Parse(baseURL)
+// NewClientWithOptions creates a client. GET responses are cached by ETag and
+// revalidated with conditional requests.
+func NewClientWithOptions(opts ClientOptions) (*Client, error) {
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.github.com"
+	}
+	// Ensure trailing slash for GitHub API
+	if baseURL[len(baseURL)-1] != '/' {
+		baseURL += "/"
+	}
+	apiURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	var app *appAuth
+	var auth http.RoundTripper
+	switch {
+	case opts.App != nil:
+		app, err = newAppAuth(*opts.App, apiURL)
 		if err != nil {
-			return nil, fmt.Errorf("invalid base URL: %w", err)
+			return nil, err
 		}
-		client.BaseURL = parsedURL
+		auth = &oauth2.Transport{Source: app.tokenSource()}
+	case opts.TokenRefresh != nil:
+		// Not wrapped in a ReuseTokenSource, which would keep the first token forever
+		tokens := &refreshingTokenSource{token: opts.Token, refresh: opts.TokenRefresh}
+		auth = &reauthTransport{base: &oauth2.Transport{Source: tokens}, tokens: tokens}
+	default:
+		auth = &oauth2.Transport{Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: opts.Token})}
 	}
 
-	var repoName string
-	if len(repo) > 0 {
-		repoName = repo[0]
+	tc := &http.Client{}
+	cache := newResponseCache(opts.CacheFile)
+	stats := &statsTransport{base: &cacheTransport{base: auth, cache: cache}}
+	limiter := &rateLimiter{}
+	var transport http.RoundTripper = stats
+	if opts.RequestsPerSecond > 0 {
+		transport = &budgetTransport{base: stats, budget: newRequestBudget(opts.RequestsPerSecond)}
 	}
+	tc.Transport = &rateLimitTransport{base: transport, limiter: limiter}
+
+	client := github.NewClient(tc)
+	client.BaseURL = apiURL
 
 	return &Client{
-		client: client,
-		org:    org,
-		repo:   repoName,
+		client:  client,
+		org:     opts.Org,
+		repo:    opts.Repo,
+		stats:   stats,
+		limiter: limiter,
+		cache:   cache,
+		app:     app,
 	}, nil
 }
 
+// WithOrganization returns a client for another organization that shares the same credentials
+func (c *Client) WithOrganization(org string) *Client {
+	return &Client{
+		client:  c.client,
+		org:     org,
+		stats:   c.stats,
+		limiter: c.limiter,
+		cache:   c.cache,
+		app:     c.app,
+	}
+}
+
+// GetOrganization returns the organization the client operates on
+func (c *Client) GetOrganization() string {
+	return c.org
+}
+
 // addOptions adds the parameters in opts as URL query parameters to s.
 func addOptions(s string, opts interface{}) (string, error) {
 	v := reflect.ValueOf(opts)
@@ -126,17 +218,27 @@ func (c *Client) CancelWorkflowRun(ctx context.Context, repo string, runID int64
 
 // ApprovePendingDeployment approves a pending deployment for a workflow run
 func (c *Client) ApprovePendingDeployment(ctx context.Context, repo string, runID int64, environmentIDs []int64, comment string) (*github.Response, error) {
+	return c.reviewPendingDeployment(ctx, repo, runID, environmentIDs, "approved", comment)
+}
+
+// RejectPendingDeployment rejects a pending deployment for a workflow run
+func (c *Client) RejectPendingDeployment(ctx context.Context, repo string, runID int64, environmentIDs []int64, comment string) (*github.Response, error) {
+	return c.reviewPendingDeployment(ctx, repo, runID, environmentIDs, "rejected", comment)
+}
+
+// reviewPendingDeployment posts a review with the given state ("approved" or "rejected")
+func (c *Client) reviewPendingDeployment(ctx context.Context, repo string, runID int64, environmentIDs []int64, state, comment string) (*github.Response, error) {
 	u := fmt.Sprintf("repos/%v/%v/actions/runs/%v/pending_deployments", c.org, repo, runID)
 	
-	type approvalRequest struct {
+	type reviewRequest struct {
 		EnvironmentIDs []int64 `json:"environment_ids"`
 		State          string  `json:"state"`
 		Comment        string  `json:"comment"`
 	}
 	
-	req := &approvalRequest{
+	req := &reviewRequest{
 		EnvironmentIDs: environmentIDs,
-		State:          "approved",
+		State:          state,
 		Comment:        comment,
 	}
 	
@@ -162,10 +264,50 @@ type PendingDeployment struct {
 	WaitTimer            int    `json:"wait_timer"`
 	WaitTimerStartedAt   string `json:"wait_timer_started_at"`
 	CurrentUserCanApprove bool   `json:"current_user_can_approve"`
-	Reviewers            []struct {
-		Type string `json:"type"`
-		ID   int64  `json:"id"`
-	} `json:"reviewers"`
+	Reviewers            []PendingDeploymentReviewer `json:"reviewers"`
+}
+
+// PendingDeploymentReviewer represents a user or team required to review a pending deployment
+type PendingDeploymentReviewer struct {
+	Type     string `json:"type"`
+	ID       int64  `json:"id"`
+	Reviewer struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login,omitempty"`
+		Name  string `json:"name,omitempty"`
+		Slug  string `json:"slug,omitempty"`
+	} `json:"reviewer"`
+}
+
+// DisplayName returns the login of a user reviewer or the slug of a team reviewer
+func (r PendingDeploymentReviewer) DisplayName() string {
+	switch {
+	case r.Reviewer.Login != "":
+		return r.Reviewer.Login
+	case r.Reviewer.Slug != "":
+		return r.Reviewer.Slug
+	case r.Reviewer.Name != "":
+		return r.Reviewer.Name
+	default:
+		return fmt.Sprintf("%s#%d", r.Type, r.Reviewer.ID)
+	}
+}
+
+// EnvironmentName returns the name of the environment waiting for review
+func (pd *PendingDeployment) EnvironmentName() string {
+	if pd.Environment.Name != nil {
+		return *pd.Environment.Name
+	}
+	return ""
+}
+
+// ReviewerNames returns the display names of the required reviewers
+func (pd *PendingDeployment) ReviewerNames() []string {
+	names := make([]string, 0, len(pd.Reviewers))
+	for _, r := range pd.Reviewers {
+		names = append(names, r.DisplayName())
+	}
+	return names
 }
 
 // GetPendingDeployments gets pending deployments for a workflow run
@@ -196,13 +338,88 @@ func (c *Client) GetWorkflowJob(ctx context.Context, repo string, jobID int64) (
 	return c.client.Actions.GetWorkflowJobByID(ctx, c.org, repo, jobID)
 }
 
+// GetWorkflowJobLogs downloads the plain text logs of a workflow job.
+// The API answers with a redirect to a short-lived download URL, which is fetched without credentials.
+// Only the last maxJobLogBytes of a longer log are returned, after a line saying so.
+func (c *Client) GetWorkflowJobLogs(ctx context.Context, repo string, jobID int64) (string, *github.Response, error) {
+	logURL, resp, err := c.client.Actions.GetWorkflowJobLogs(ctx, c.org, repo, jobID, 3)
+	if err != nil {
+		return "", resp, err
+	}
+	
+	request, err := http.NewRequestWithContext(ctx, "GET", logURL.String(), nil)
+	if err != nil {
+		return "", resp, err
+	}
+	
+	download, err := logClient.Do(request)
+	if err != nil {
+		return "", resp, fmt.Errorf("failed to download logs: %w", err)
+	}
+	defer download.Body.Close()
+	
+	if download.StatusCode != http.StatusOK {
+		return "", resp, fmt.Errorf("failed to download logs: %s", download.Status)
+	}
+	
+	body, truncated, err := readTail(io.LimitReader(download.Body, maxJobLogDownload), maxJobLogBytes)
+	if err != nil {
+		return "", resp, fmt.Errorf("failed to read logs: %w", err)
+	}
+	if !truncated {
+		return string(body), resp, nil
+	}
+	
+	// Drop the partial line the kept part starts with
+	if i := bytes.IndexByte(body, '\n'); i >= 0 {
+		body = body[i+1:]
+	}
+	return fmt.Sprintf("[cocd] Log truncated, showing its last %d MiB\n", maxJobLogBytes>>20) + string(body), resp, nil
+}
+
+// readTail reads r to the end and returns its last limit bytes, and whether
+// anything before them was dropped. Memory stays within twice the limit.
+func readTail(r io.Reader, limit int) ([]byte, bool, error) {
+	buf := make([]byte, 0, 64<<10)
+	chunk := make([]byte, 32<<10)
+	truncated := false
+	for {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if len(buf) > 2*limit {
+			buf = append(buf[:0], buf[len(buf)-limit:]...)
+			truncated = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	
+	if len(buf) > limit {
+		buf = buf[len(buf)-limit:]
+		truncated = true
+	}
+	return buf, truncated, nil
+}
+
 // GetContents gets the contents of a file or directory
 func (c *Client) GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
 	return c.client.Repositories.GetContents(ctx, owner, repo, path, opts)
 }
 
-// GetAuthenticatedUser gets information about the authenticated user
+// GetAuthenticatedUser gets information about the authenticated user. A GitHub App
+// installation has no user, so its bot account is returned with only the login set.
 func (c *Client) GetAuthenticatedUser(ctx context.Context) (*github.User, *github.Response, error) {
+	if c.app != nil {
+		login, err := c.app.login(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &github.User{Login: github.String(login)}, nil, nil
+	}
 	return c.client.Users.Get(ctx, "")
 }
 
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GraphQLError is an error reported in the errors of a GraphQL response. Path
+// leads to the field that failed, starting with its alias.
+type GraphQLError struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+func (e GraphQLError) Error() string {
+	return e.Message
+}
+
+// GraphQLErrors are the errors of a GraphQL response that may still carry data
+// for the fields that did not fail
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+	return "GraphQL: " + strings.Join(messages, "; ")
+}
+
+// GraphQL sends a query to the GraphQL API and decodes its data into v. When the
+// response reports errors, v holds the partial data and GraphQLErrors is returned.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, v interface{}) error {
+	body := struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables,omitempty"`
+	}{query, variables}
+
+	request, err := c.client.NewRequest("POST", c.graphQLURL(), body)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	if _, err := c.client.Do(ctx, request, &response); err != nil {
+		return err
+	}
+
+	if len(response.Data) > 0 && string(response.Data) != "null" {
+		if err := json.Unmarshal(response.Data, v); err != nil {
+			return fmt.Errorf("invalid GraphQL response: %w", err)
+		}
+	}
+	if len(response.Errors) > 0 {
+		return response.Errors
+	}
+	return nil
+}
+
+// graphQLURL returns the GraphQL endpoint next to the REST API. GitHub Enterprise
+// Server serves it at /api/graphql rather than below /api/v3/.
+func (c *Client) graphQLURL() string {
+	endpoint := *c.client.BaseURL
+	if strings.HasSuffix(endpoint.Path, "/api/v3/") {
+		endpoint.Path = strings.TrimSuffix(endpoint.Path, "v3/") + "graphql"
+		return endpoint.String()
+	}
+	return endpoint.JoinPath("graphql").String()
+}
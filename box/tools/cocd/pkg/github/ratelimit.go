@@ -0,0 +1,208 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries is how often a request hit by a rate limit is sent again
+	maxRateLimitRetries = 3
+	// secondaryRateLimitBackoff is the first wait after a secondary rate limit
+	// without Retry-After, doubled on every retry as GitHub recommends
+	secondaryRateLimitBackoff = time.Minute
+)
+
+// ErrRateLimited is returned instead of waiting for a rate limit reset that is
+// later than the deadline of the request
+var ErrRateLimited = errors.New("GitHub API rate limit exceeded")
+
+// rateLimiter tracks the rate limit headers of every response and holds back
+// requests while the limit is exhausted
+type rateLimiter struct {
+	mu          sync.Mutex
+	limit       int
+	remaining   int
+	reset       time.Time
+	pausedUntil time.Time
+}
+
+// record stores the rate limit headers of a response. Only the REST API limit
+// is kept; GraphQL and search requests count against limits of their own.
+func (l *rateLimiter) record(resp *http.Response) {
+	if resource := resp.Header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		l.limit = limit
+	}
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		l.remaining = remaining
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		l.reset = time.Unix(reset, 0)
+	}
+}
+
+// pause holds back all requests until the given time
+func (l *rateLimiter) pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// wait blocks while requests are paused
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	until := l.pausedUntil
+	l.mu.Unlock()
+
+	return sleepUntil(ctx, until)
+}
+
+// fill copies the rate limit state into stats
+func (l *rateLimiter) fill(stats *APIStats) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats.RateLimit = l.limit
+	stats.RateRemaining = l.remaining
+	stats.RateReset = l.reset
+	if time.Now().Before(l.pausedUntil) {
+		stats.PausedUntil = l.pausedUntil
+	}
+}
+
+// rateLimitTransport pauses all requests of a client while its rate limit is
+// exhausted and retries requests that hit the primary or a secondary rate limit
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		t.limiter.record(resp)
+
+		until, limited := rateLimitedUntil(resp, attempt)
+		if until.IsZero() {
+			return resp, nil
+		}
+		t.limiter.pause(until)
+
+		if !limited {
+			// The last request of the window succeeded; the pause holds back the
+			// next request until the reset so that it is not refused right away
+			return resp, nil
+		}
+
+		if attempt >= maxRateLimitRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		if deadline, ok := req.Context().Deadline(); ok && deadline.Before(until) {
+			return resp, nil
+		}
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			retry.Body = body
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		req = retry
+	}
+}
+
+// rateLimitedUntil returns until when a response asks to pause requests, and
+// whether the request itself was refused. The zero time means no pause is needed.
+// Like record, only an exhausted REST API limit pauses requests.
+func rateLimitedUntil(resp *http.Response, attempt int) (time.Time, bool) {
+	reset := time.Time{}
+	if seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(seconds, 0)
+	}
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	exhausted := (resource == "" || resource == "core") &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0" && reset.After(time.Now())
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		if exhausted {
+			return reset, false
+		}
+		return time.Time{}, false
+	}
+
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(retryAfter) * time.Second), true
+	}
+	if exhausted {
+		return reset, true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || isSecondaryRateLimit(resp) {
+		return time.Now().Add(secondaryRateLimitBackoff << attempt), true
+	}
+
+	// Any other 403 is a permission problem
+	return time.Time{}, false
+}
+
+// isSecondaryRateLimit reports whether a 403 response is a secondary rate limit,
+// which GitHub only tells in the message. The body is left readable.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// sleepUntil waits until the given time, failing right away when the context
+// would expire before it
+func sleepUntil(ctx context.Context, until time.Time) error {
+	wait := time.Until(until)
+	if wait <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(until) {
+		return fmt.Errorf("%w, requests are paused until %s", ErrRateLimited, until.Format(time.TimeOnly))
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
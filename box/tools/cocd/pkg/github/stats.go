@@ -0,0 +1,61 @@
+package github
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// APIStats summarizes the GitHub API calls made by a client
+type APIStats struct {
+	Requests  uint64 // Requests sent, including failed ones
+	Errors    uint64 // Requests that failed or got a 4xx/5xx response
+	CacheHits uint64 // Requests answered 304 Not Modified and served from the response cache
+
+	// Rate limit reported by the last response, zero until one carried the headers
+	RateLimit     int
+	RateRemaining int
+	RateReset     time.Time
+	// PausedUntil is set while requests wait for an exhausted rate limit to reset
+	PausedUntil time.Time
+}
+
+// statsTransport counts every request sent through it, including retries
+type statsTransport struct {
+	base http.RoundTripper
+
+	mu    sync.Mutex
+	stats APIStats
+}
+
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stats.Requests++
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		t.stats.Errors++
+	}
+
+	return resp, err
+}
+
+func (t *statsTransport) snapshot() APIStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+// Stats returns the API call statistics of the client, shared with clients
+// created by WithOrganization
+func (c *Client) Stats() APIStats {
+	if c.stats == nil {
+		return APIStats{}
+	}
+	stats := c.stats.snapshot()
+	c.limiter.fill(&stats)
+	stats.CacheHits = c.cache.hitCount()
+	return stats
+}
@@ -0,0 +1,78 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// refreshingTokenSource holds a token that is read again from its source when
+// GitHub rejects it
+type refreshingTokenSource struct {
+	refresh func() (string, error)
+
+	mu    sync.Mutex
+	token string
+}
+
+func (s *refreshingTokenSource) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: s.current()}, nil
+}
+
+func (s *refreshingTokenSource) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// renew reads the token again after rejected was refused, and reports whether
+// a different token is now in use. Requests refused together only read it once.
+func (s *refreshingTokenSource) renew(rejected string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != rejected {
+		return true
+	}
+	token, err := s.refresh()
+	if err != nil || token == "" || token == rejected {
+		return false
+	}
+	s.token = token
+	return true
+}
+
+// reauthTransport sends a request again with a renewed token when it is
+// refused with 401 Unauthorized
+type reauthTransport struct {
+	base   http.RoundTripper
+	tokens *refreshingTokenSource
+}
+
+func (t *reauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	used := t.tokens.current()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	if !t.tokens.renew(used) {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
@@ -0,0 +1,335 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/younsl/cocd/pkg/scanner"
+	bolt "go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+)
+
+// openTimeout is how long Open waits for another cocd to release the store
+const openTimeout = time.Second
+
+// Kinds of transitions
+const (
+	KindObserved = "observed" // The run was seen for the first time
+	KindStatus   = "status"   // The run changed its status
+	KindApproved = "approved" // A deployment of the run was approved from cocd
+	KindRejected = "rejected" // A deployment of the run was rejected from cocd
+)
+
+var (
+	runsBucket        = []byte("runs")
+	transitionsBucket = []byte("transitions")
+)
+
+// Run is a workflow run as it was last observed
+type Run struct {
+	Job       scanner.JobStatus `json:"job"`
+	FirstSeen time.Time         `json:"first_seen"`
+	LastSeen  time.Time         `json:"last_seen"`
+	// Waited is set once the run was seen waiting for a deployment approval
+	Waited bool `json:"waited,omitempty"`
+}
+
+// Transition is a change of a run: its first sighting, a new status or a review
+type Transition struct {
+	Kind           string    `json:"kind"`
+	Time           time.Time `json:"time"`
+	Repository     string    `json:"repository"`
+	RunID          int64     `json:"run_id"`
+	RunNumber      int       `json:"run_number"`
+	WorkflowName   string    `json:"workflow_name"`
+	Status         string    `json:"status,omitempty"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	// Detail is the review comment or the environments a review applied to
+	Detail string `json:"detail,omitempty"`
+}
+
+// Store keeps the observed workflow runs and their transitions in a bbolt
+// file, in one bucket per organization. Only one process can open it at a time.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens or creates the store at path and drops what was last seen more
+// than retention ago. Zero retention keeps everything.
+func Open(path string, retention time.Duration) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if errors.Is(err, bolterrors.ErrTimeout) {
+		return nil, fmt.Errorf("history %s is in use by another cocd", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+
+	s := &Store{db: db}
+	if retention > 0 {
+		if err := s.prune(time.Now().Add(-retention)); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to prune history: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Close releases the store for other processes
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record saves a snapshot of runs observed at a time and the transitions since
+// the previous snapshot of each run. A snapshot older than the one saved, such
+// as the result of a slow scan that finished after a newer one, is ignored.
+func (s *Store) Record(org string, jobs []scanner.JobStatus, at time.Time) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		runs, transitions, err := createOrgBuckets(tx, org)
+		if err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+			key := runKey(job.Repository, job.RunID)
+
+			var run Run
+			data := runs.Get(key)
+			switch {
+			case data == nil || json.Unmarshal(data, &run) != nil:
+				run = Run{FirstSeen: at}
+				if err := addTransition(transitions, newTransition(KindObserved, job, at)); err != nil {
+					return err
+				}
+			case isOlder(job, run.Job):
+				continue
+			case job.Status != run.Job.Status:
+				transition := newTransition(KindStatus, job, at)
+				transition.PreviousStatus = run.Job.Status
+				if err := addTransition(transitions, transition); err != nil {
+					return err
+				}
+			}
+
+			run.Job = job
+			run.LastSeen = at
+			run.Waited = run.Waited || job.Status == "waiting"
+			if err := putJSON(runs, key, run); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RecordReview saves the approval or rejection of a run, kind being KindApproved or KindRejected
+func (s *Store) RecordReview(org, kind string, job scanner.JobStatus, detail string, at time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		_, transitions, err := createOrgBuckets(tx, org)
+		if err != nil {
+			return err
+		}
+		transition := newTransition(kind, job, at)
+		transition.Detail = detail
+		return addTransition(transitions, transition)
+	})
+}
+
+// Runs returns up to limit runs of an organization, the most recently started first
+func (s *Store) Runs(org string, limit int) ([]Run, error) {
+	var runs []Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := orgBucket(tx, org, runsBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, data []byte) error {
+			var run Run
+			if err := json.Unmarshal(data, &run); err != nil {
+				return nil
+			}
+			runs = append(runs, run)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		a, b := runs[i].Job.StartedAt, runs[j].Job.StartedAt
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.After(*b)
+	})
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+// Transitions returns the transitions of an organization since a time, oldest first
+func (s *Store) Transitions(org string, since time.Time) ([]Transition, error) {
+	var result []Transition
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := orgBucket(tx, org, transitionsBucket)
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for key, data := cursor.Seek(timeKey(since)); key != nil; key, data = cursor.Next() {
+			var transition Transition
+			if err := json.Unmarshal(data, &transition); err != nil {
+				continue
+			}
+			result = append(result, transition)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// prune deletes the runs last seen and the transitions made before cutoff
+func (s *Store) prune(cutoff time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(_ []byte, org *bolt.Bucket) error {
+			if runs := org.Bucket(runsBucket); runs != nil {
+				var expired [][]byte
+				err := runs.ForEach(func(key, data []byte) error {
+					var run Run
+					if json.Unmarshal(data, &run) != nil || run.LastSeen.Before(cutoff) {
+						expired = append(expired, append([]byte(nil), key...))
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				if err := deleteKeys(runs, expired); err != nil {
+					return err
+				}
+			}
+
+			if transitions := org.Bucket(transitionsBucket); transitions != nil {
+				// Transition keys start with their time, so the expired ones come first
+				var expired [][]byte
+				end := timeKey(cutoff)
+				cursor := transitions.Cursor()
+				for key, _ := cursor.First(); key != nil && bytes.Compare(key, end) < 0; key, _ = cursor.Next() {
+					expired = append(expired, append([]byte(nil), key...))
+				}
+				if err := deleteKeys(transitions, expired); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// createOrgBuckets returns the runs and transitions buckets of an organization, creating them if needed
+func createOrgBuckets(tx *bolt.Tx, org string) (*bolt.Bucket, *bolt.Bucket, error) {
+	bucket, err := tx.CreateBucketIfNotExists(orgKey(org))
+	if err != nil {
+		return nil, nil, err
+	}
+	runs, err := bucket.CreateBucketIfNotExists(runsBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	transitions, err := bucket.CreateBucketIfNotExists(transitionsBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	return runs, transitions, nil
+}
+
+// orgBucket returns a bucket of an organization, nil if nothing was recorded for it
+func orgBucket(tx *bolt.Tx, org string, name []byte) *bolt.Bucket {
+	bucket := tx.Bucket(orgKey(org))
+	if bucket == nil {
+		return nil
+	}
+	return bucket.Bucket(name)
+}
+
+// orgKey names the bucket of an organization, whose logins are case-insensitive
+func orgKey(org string) []byte {
+	return []byte(strings.ToLower(org))
+}
+
+func runKey(repo string, runID int64) []byte {
+	return []byte(fmt.Sprintf("%s/%d", repo, runID))
+}
+
+// timeKey encodes a time so that byte order is chronological order. Times
+// before 1970, such as the zero time, all map to the first key.
+func timeKey(t time.Time) []byte {
+	var nanos uint64
+	if t.After(time.Unix(0, 0)) {
+		nanos = uint64(t.UnixNano())
+	}
+	return binary.BigEndian.AppendUint64(nil, nanos)
+}
+
+// addTransition appends a transition, keyed by its time and a sequence that
+// keeps transitions recorded in the same instant apart
+func addTransition(bucket *bolt.Bucket, transition Transition) error {
+	sequence, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	key := binary.BigEndian.AppendUint64(timeKey(transition.Time), sequence)
+	return putJSON(bucket, key, transition)
+}
+
+func newTransition(kind string, job scanner.JobStatus, at time.Time) Transition {
+	return Transition{
+		Kind:         kind,
+		Time:         at,
+		Repository:   job.Repository,
+		RunID:        job.RunID,
+		RunNumber:    job.RunNumber,
+		WorkflowName: job.WorkflowName,
+		Status:       job.Status,
+	}
+}
+
+// isOlder reports whether job was observed before saved, judging by when GitHub last updated the run
+func isOlder(job, saved scanner.JobStatus) bool {
+	return job.CompletedAt != nil && saved.CompletedAt != nil && job.CompletedAt.Before(*saved.CompletedAt)
+}
+
+func putJSON(bucket *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
+
+func deleteKeys(bucket *bolt.Bucket, keys [][]byte) error {
+	for _, key := range keys {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
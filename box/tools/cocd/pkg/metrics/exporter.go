@@ -0,0 +1,204 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ghclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+const namespace = "cocd"
+
+// Exporter exposes the results of periodic scans as Prometheus metrics
+type Exporter struct {
+	monitor  *monitor.Monitor
+	registry *prometheus.Registry
+
+	waitingRuns        *prometheus.GaugeVec
+	oldestWaitingAge   *prometheus.GaugeVec
+	runsByStatus       *prometheus.GaugeVec
+	scanDuration       prometheus.Histogram
+	scans              *prometheus.CounterVec
+	lastScan           prometheus.Gauge
+	repositoriesTotal  prometheus.Gauge
+	repositoriesActive prometheus.Gauge
+	repositoriesFailed prometheus.Gauge
+
+	// observeMu keeps the run gauges of concurrent scans and webhook updates apart
+	observeMu sync.Mutex
+}
+
+// NewExporter creates an exporter for a monitor. API call counts and the rate limit
+// are read from the monitor's GitHub client when Prometheus scrapes.
+func NewExporter(mon *monitor.Monitor) *Exporter {
+	e := &Exporter{
+		monitor:  mon,
+		registry: prometheus.NewRegistry(),
+
+		waitingRuns: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "waiting_runs",
+			Help:      "Workflow runs waiting for a deployment approval.",
+		}, []string{"repository", "environment"}),
+		oldestWaitingAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "oldest_waiting_run_age_seconds",
+			Help:      "Age of the oldest workflow run waiting for a deployment approval.",
+		}, []string{"repository", "environment"}),
+		runsByStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "runs",
+			Help:      "Recent workflow runs by status.",
+		}, []string{"status"}),
+		scanDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "scan_duration_seconds",
+			Help:      "Duration of organization scans.",
+			Buckets:   []float64{5, 10, 20, 30, 45, 60, 90, 120},
+		}),
+		scans: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scans_total",
+			Help:      "Organization scans by result.",
+		}, []string{"result"}),
+		lastScan: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_scan_timestamp_seconds",
+			Help:      "Unix time of the last successful scan.",
+		}),
+		repositoriesTotal: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "repositories",
+			Help:      "Repositories in the organization, excluding archived and disabled ones.",
+		}),
+		repositoriesActive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "repositories_scanned",
+			Help:      "Recently active repositories scanned by the last scan.",
+		}),
+		repositoriesFailed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "repositories_failed",
+			Help:      "Repositories the last scan failed to read, whose runs are missing from the run metrics.",
+		}),
+	}
+
+	e.registry.MustRegister(
+		e.waitingRuns,
+		e.oldestWaitingAge,
+		e.runsByStatus,
+		e.scanDuration,
+		e.scans,
+		e.lastScan,
+		e.repositoriesTotal,
+		e.repositoriesActive,
+		e.repositoriesFailed,
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_requests_total",
+			Help:      "GitHub API requests sent.",
+		}, func() float64 { return float64(e.apiStats().Requests) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_errors_total",
+			Help:      "GitHub API requests that failed or got an error response.",
+		}, func() float64 { return float64(e.apiStats().Errors) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_cache_hits_total",
+			Help:      "GitHub API requests answered 304 Not Modified and served from the response cache.",
+		}, func() float64 { return float64(e.apiStats().CacheHits) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_remaining",
+			Help:      "GitHub API requests remaining in the current rate limit window.",
+		}, func() float64 { return float64(e.apiStats().RateRemaining) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rate_limit",
+			Help:      "GitHub API requests allowed per rate limit window.",
+		}, func() float64 { return float64(e.apiStats().RateLimit) }),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return e
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveScan records the outcome of a scan started by monitor.StartScanning
+func (e *Exporter) ObserveScan(result monitor.ScanResult) {
+	e.scanDuration.Observe(result.Duration.Seconds())
+	if result.Err != nil {
+		e.scans.WithLabelValues("error").Inc()
+		return
+	}
+	e.scans.WithLabelValues("success").Inc()
+	e.lastScan.Set(float64(result.At.Unix()))
+
+	progress := e.monitor.GetScanProgress()
+	e.repositoriesTotal.Set(float64(progress.ValidRepos))
+	e.repositoriesActive.Set(float64(progress.ActiveRepos))
+	e.repositoriesFailed.Set(float64(len(result.RepoErrors)))
+
+	e.Observe(result.Jobs, result.At)
+}
+
+// Observe replaces the run gauges with the state of a scan
+func (e *Exporter) Observe(jobs []scanner.JobStatus, now time.Time) {
+	e.observeMu.Lock()
+	defer e.observeMu.Unlock()
+
+	e.waitingRuns.Reset()
+	e.oldestWaitingAge.Reset()
+	e.runsByStatus.Reset()
+
+	oldest := make(map[[2]string]float64)
+	for _, job := range jobs {
+		e.runsByStatus.WithLabelValues(job.Status).Inc()
+		if job.Status != "waiting" {
+			continue
+		}
+
+		var age float64
+		if job.StartedAt != nil {
+			age = now.Sub(*job.StartedAt).Seconds()
+		}
+
+		for _, environment := range waitingEnvironments(job) {
+			e.waitingRuns.WithLabelValues(job.Repository, environment).Inc()
+			key := [2]string{job.Repository, environment}
+			if current, ok := oldest[key]; !ok || age > current {
+				oldest[key] = age
+			}
+		}
+	}
+
+	for key, age := range oldest {
+		e.oldestWaitingAge.WithLabelValues(key[0], key[1]).Set(age)
+	}
+}
+
+// waitingEnvironments returns the environments a waiting run is waiting on, or
+// "unknown" when they could not be fetched
+func waitingEnvironments(job scanner.JobStatus) []string {
+	if job.Environment == "" {
+		return []string{"unknown"}
+	}
+	return strings.Split(job.Environment, ", ")
+}
+
+func (e *Exporter) apiStats() ghclient.APIStats {
+	return e.monitor.GetClient().Stats()
+}
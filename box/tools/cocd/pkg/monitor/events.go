@@ -0,0 +1,189 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+// EventType is a state transition of a workflow run
+type EventType string
+
+const (
+	EventWaiting   EventType = "waiting"   // Run is waiting for a deployment approval
+	EventApproved  EventType = "approved"  // Run left the waiting state and continued
+	EventStarted   EventType = "started"   // Run started running
+	EventCompleted EventType = "completed" // Run finished without failing
+	EventCancelled EventType = "cancelled" // Run was cancelled
+	EventFailed    EventType = "failed"    // Run failed or timed out
+)
+
+// EventTypes lists every event type
+var EventTypes = []EventType{EventWaiting, EventApproved, EventStarted, EventCompleted, EventCancelled, EventFailed}
+
+// DefaultEventRetention is how long finished runs are remembered after leaving the snapshots
+const DefaultEventRetention = 24 * time.Hour
+
+// Event is a state transition of a workflow run between two snapshots
+type Event struct {
+	Type           EventType         `json:"type"`
+	Time           time.Time         `json:"time"`
+	PreviousStatus string            `json:"previous_status,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Job            scanner.JobStatus `json:"job"`
+}
+
+// runPhase groups run statuses into the states events are emitted for
+type runPhase int
+
+const (
+	phaseQueued runPhase = iota
+	phaseWaiting
+	phaseRunning
+	phaseCompleted
+	phaseCancelled
+	phaseFailed
+)
+
+func phaseOf(status string) runPhase {
+	switch status {
+	case "waiting":
+		return phaseWaiting
+	case "in_progress":
+		return phaseRunning
+	case "queued", "pending", "requested":
+		return phaseQueued
+	case "cancelled":
+		return phaseCancelled
+	case "failure", "timed_out", "startup_failure":
+		return phaseFailed
+	default:
+		// success, neutral, skipped and other conclusions
+		return phaseCompleted
+	}
+}
+
+func (p runPhase) finished() bool {
+	return p >= phaseCompleted
+}
+
+// event returns the event emitted when a run enters a phase, if any
+func (p runPhase) event() (EventType, bool) {
+	switch p {
+	case phaseWaiting:
+		return EventWaiting, true
+	case phaseRunning:
+		return EventStarted, true
+	case phaseCompleted:
+		return EventCompleted, true
+	case phaseCancelled:
+		return EventCancelled, true
+	case phaseFailed:
+		return EventFailed, true
+	default:
+		return "", false
+	}
+}
+
+// EventDiffer turns successive job snapshots into run state transitions.
+// The first snapshot is a baseline that only reports runs already waiting.
+type EventDiffer struct {
+	retention time.Duration
+	started   time.Time
+	seen      map[string]scanner.JobStatus
+	lastSeen  map[string]time.Time // When each run was last in a snapshot
+	baseline  bool
+}
+
+// NewEventDiffer creates a differ that remembers finished runs for retention
+func NewEventDiffer(retention time.Duration) *EventDiffer {
+	return &EventDiffer{
+		retention: retention,
+		seen:      make(map[string]scanner.JobStatus),
+		lastSeen:  make(map[string]time.Time),
+	}
+}
+
+// Diff compares a snapshot with the runs seen so far and returns the transitions in snapshot order
+func (d *EventDiffer) Diff(jobs []scanner.JobStatus, now time.Time) []Event {
+	var events []Event
+
+	if !d.baseline {
+		d.baseline = true
+		d.started = now
+		for _, job := range jobs {
+			d.seen[runKey(job)] = job
+			d.lastSeen[runKey(job)] = now
+			if phaseOf(job.Status) == phaseWaiting {
+				events = append(events, Event{Type: EventWaiting, Time: now, Job: job})
+			}
+		}
+		return events
+	}
+
+	for _, job := range jobs {
+		key := runKey(job)
+		phase := phaseOf(job.Status)
+		previous, known := d.seen[key]
+		d.seen[key] = job
+		d.lastSeen[key] = now
+
+		if !known {
+			// Runs of repositories that just entered the scan can have finished long ago
+			if phase.finished() && (job.CompletedAt == nil || job.CompletedAt.Before(d.started)) {
+				continue
+			}
+			if eventType, ok := phase.event(); ok {
+				events = append(events, Event{Type: eventType, Time: now, Job: job})
+			}
+			continue
+		}
+
+		previousPhase := phaseOf(previous.Status)
+		if phase == previousPhase {
+			continue
+		}
+
+		// A rejected run fails, so only runs that went on count as approved
+		if previousPhase == phaseWaiting && phase != phaseCancelled && phase != phaseFailed {
+			events = append(events, Event{Type: EventApproved, Time: now, PreviousStatus: previous.Status, Job: job})
+		}
+		if eventType, ok := phase.event(); ok {
+			events = append(events, Event{Type: eventType, Time: now, PreviousStatus: previous.Status, Job: job})
+		}
+	}
+
+	d.prune(jobs, now)
+	return events
+}
+
+// prune forgets runs that left the snapshots: finished runs once they completed
+// more than retention ago, unfinished ones once they were last seen more than
+// retention ago, such as a waiting run pushed out of the scan window or one
+// approved or cancelled while it was out of sight
+func (d *EventDiffer) prune(jobs []scanner.JobStatus, now time.Time) {
+	current := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		current[runKey(job)] = true
+	}
+
+	for key, job := range d.seen {
+		if current[key] {
+			continue
+		}
+
+		expired := now.Sub(d.lastSeen[key]) > d.retention
+		if phaseOf(job.Status).finished() {
+			expired = job.CompletedAt == nil || now.Sub(*job.CompletedAt) > d.retention
+		}
+		if expired {
+			delete(d.seen, key)
+			delete(d.lastSeen, key)
+		}
+	}
+}
+
+func runKey(job scanner.JobStatus) string {
+	return fmt.Sprintf("%s:%d", job.Repository, job.RunID)
+}
@@ -2,6 +2,9 @@ package monitor
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	ghclient "github.com/younsl/cocd/pkg/github"
@@ -9,7 +12,7 @@ import (
 )
 
 const (
-	DefaultWorkerPoolSize    = 1
+	DefaultWorkerPoolSize    = 4
 	DefaultScanTimeout       = 60 * time.Second
 	DefaultRecentScanTimeout = 90 * time.Second
 	
@@ -25,29 +28,77 @@ const (
 )
 
 type Monitor struct {
+	// mu guards the organization-specific fields, which are replaced by SwitchOrganization
+	mu             sync.RWMutex
 	client         *ghclient.Client
 	repoManager    *RepositoryManager
 	progressTracker *ProgressTracker
 	
-	recentScanner *scanner.RecentJobsScanner
+	recentScanner scanner.Scanner
+	// waitingScanner asks GitHub for the waiting runs only, with REST whatever
+	// the strategy so that runs waiting on older commits are found
+	waitingScanner scanner.Scanner
 	
 	interval    time.Duration
+	workers     int
+	strategy    string
 	
+	// repoErrors are the repositories the last scan failed to read
+	repoErrors []RepoError
+}
+
+// Scan strategies, how the workflow runs of the repositories are read
+const (
+	ScanStrategyREST    = "rest"    // One REST request per repository
+	ScanStrategyGraphQL = "graphql" // One GraphQL query per batch of repositories
+)
+
+// Options configures a monitor created by NewMonitorWithOptions
+type Options struct {
+	Interval int    // Scan interval in seconds
+	Workers  int    // Repositories scanned concurrently, DefaultWorkerPoolSize when zero
+	Strategy string // ScanStrategyREST when empty
 }
 
 func NewMonitor(client *ghclient.Client, interval int) *Monitor {
+	return NewMonitorWithOptions(client, Options{Interval: interval})
+}
+
+// NewMonitorWithOptions creates a monitor
+func NewMonitorWithOptions(client *ghclient.Client, opts Options) *Monitor {
 	repoManager := NewRepositoryManager(client)
 	progressTracker := NewProgressTracker()
 	
-	recentScanner := scanner.NewRecentJobsScanner(client)
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultWorkerPoolSize
+	}
 	
 	return &Monitor{
 		client:          client,
 		repoManager:     repoManager,
 		progressTracker: progressTracker,
-		recentScanner:   recentScanner,
-		interval:        time.Duration(interval) * time.Second,
+		recentScanner:   newScanner(client, opts.Strategy),
+		waitingScanner:  newWaitingScanner(client),
+		interval:        time.Duration(opts.Interval) * time.Second,
+		workers:         workers,
+		strategy:        opts.Strategy,
+	}
+}
+
+// newScanner creates the scanner of a scan strategy
+func newScanner(client *ghclient.Client, strategy string) scanner.Scanner {
+	if strategy == ScanStrategyGraphQL {
+		return scanner.NewGraphQLJobsScanner(client)
 	}
+	return scanner.NewRecentJobsScanner(client)
+}
+
+// newWaitingScanner creates the scanner of waiting runs. GraphQL cannot filter
+// runs by status and only sees the head commits of the latest branches, so
+// waiting runs are queried with REST for every strategy.
+func newWaitingScanner(client *ghclient.Client) scanner.Scanner {
+	return scanner.NewWaitingJobsScanner(client)
 }
 
 func (m *Monitor) GetProgressTracker() *ProgressTracker {
@@ -55,22 +106,72 @@ func (m *Monitor) GetProgressTracker() *ProgressTracker {
 }
 
 func (m *Monitor) GetClient() *ghclient.Client {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.client
 }
 
+// GetOrganization returns the organization being monitored
+func (m *Monitor) GetOrganization() string {
+	return m.GetClient().GetOrganization()
+}
+
+// GetCachedRepositoryNames returns the repository names from the repository cache
+func (m *Monitor) GetCachedRepositoryNames() []string {
+	repoManager, _ := m.components()
+	return repoManager.GetCachedRepositoryNames()
+}
+
+// SwitchOrganization starts monitoring another organization with the same credentials.
+// The repository list of the new organization is fetched first, so an unknown
+// organization or missing access leaves the monitor unchanged.
+func (m *Monitor) SwitchOrganization(ctx context.Context, org string) error {
+	client := m.GetClient().WithOrganization(org)
+	repoManager := NewRepositoryManager(client)
+	
+	if _, err := repoManager.GetRepositoriesWithCache(ctx); err != nil {
+		return err
+	}
+	
+	m.mu.Lock()
+	m.client = client
+	m.repoManager = repoManager
+	m.recentScanner = newScanner(client, m.strategy)
+	m.waitingScanner = newWaitingScanner(client)
+	m.mu.Unlock()
+	
+	return nil
+}
+
+// components returns the repository manager and scanner of the current organization
+func (m *Monitor) components() (*RepositoryManager, scanner.Scanner) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.repoManager, m.recentScanner
+}
+
 func (m *Monitor) GetScanProgress() ScanProgress {
+	repoManager, _ := m.components()
 	progress := m.progressTracker.GetProgress()
-	progress.CacheStatus = m.repoManager.GetCacheStatus()
-	progress.MemoryUsage = m.repoManager.GetMemoryUsage()
+	progress.CacheStatus = repoManager.GetCacheStatus()
+	progress.MemoryUsage = repoManager.GetMemoryUsage()
 	return progress
 }
 
+// RepositoryErrors returns the repositories the last scan failed to read. Their
+// jobs are missing from its result while the other repositories are complete.
+func (m *Monitor) RepositoryErrors() []RepoError {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.repoErrors
+}
+
 func (m *Monitor) GetUpdateInterval() int {
 	return int(m.interval.Seconds())
 }
 
 func (m *Monitor) GetAuthenticatedUser(ctx context.Context) (string, error) {
-	user, _, err := m.client.GetAuthenticatedUser(ctx)
+	user, _, err := m.GetClient().GetAuthenticatedUser(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -84,19 +185,18 @@ func (m *Monitor) GetPendingJobs(ctx context.Context) ([]scanner.JobStatus, erro
 	return m.GetPendingJobsWithProgress(ctx, nil)
 }
 
+// GetPendingJobsWithProgress returns the runs waiting for approval, oldest first.
+// They are queried with status=waiting, also with the GraphQL scan strategy.
 func (m *Monitor) GetPendingJobsWithProgress(ctx context.Context, progressChan chan<- ScanProgress) ([]scanner.JobStatus, error) {
-	recentJobs, err := m.GetRecentJobsWithProgress(ctx, progressChan)
+	m.mu.RLock()
+	waitingScanner := m.waitingScanner
+	m.mu.RUnlock()
+
+	waitingJobs, err := m.scanRepositories(ctx, progressChan, waitingScanner)
 	if err != nil {
 		return nil, err
 	}
 
-	var waitingJobs []scanner.JobStatus
-	for _, job := range recentJobs {
-		if job.Status == "waiting" {
-			waitingJobs = append(waitingJobs, job)
-		}
-	}
-
 	SortJobsByTime(waitingJobs, false)
 
 	return waitingJobs, nil
@@ -108,21 +208,23 @@ func (m *Monitor) GetRecentJobsWithStreaming(ctx context.Context, jobUpdateChan
 	timeoutCtx, cancel := context.WithTimeout(ctx, DefaultRecentScanTimeout)
 	defer cancel()
 
-	activeRepos, err := m.repoManager.GetActiveRepositories(timeoutCtx, MaxActiveRepositories)
+	repoManager, recentScanner := m.components()
+
+	activeRepos, err := repoManager.GetActiveRepositories(timeoutCtx, MaxActiveRepositories)
 	if err != nil {
 		return err
 	}
 
-	allRepos, err := m.repoManager.GetRepositoriesWithCache(timeoutCtx)
+	allRepos, err := repoManager.GetRepositoriesWithCache(timeoutCtx)
 	if err != nil {
 		return err
 	}
 
 	repoStats := CalculateRepoStats(allRepos)
 
-	m.progressTracker.InitializeProgress(ScanModeRecent, len(allRepos), len(activeRepos), DefaultWorkerPoolSize, repoStats)
+	m.progressTracker.InitializeProgress(ScanModeRecent, len(allRepos), len(activeRepos), m.activeWorkers(len(activeRepos)), repoStats)
 	
-	recentWorkerPool := NewWorkerPool(DefaultWorkerPoolSize, m.recentScanner)
+	recentWorkerPool := NewWorkerPool(m.workers, recentScanner)
 	
 	err = recentWorkerPool.ScanRepositoriesStreamingWithTracker(timeoutCtx, activeRepos, jobUpdateChan, m.progressTracker)
 	if err != nil {
@@ -140,43 +242,162 @@ func (m *Monitor) GetRecentJobs(ctx context.Context) ([]scanner.JobStatus, error
 }
 
 func (m *Monitor) GetRecentJobsWithProgress(ctx context.Context, progressChan chan<- ScanProgress) ([]scanner.JobStatus, error) {
+	_, recentScanner := m.components()
+
+	jobs, err := m.scanRepositories(ctx, progressChan, recentScanner)
+	if err != nil {
+		return nil, err
+	}
+
+	SortJobsByTime(jobs, true)
+
+	return jobs, nil
+}
+
+// scanRepositories scans the active repositories with sc, reporting the progress to progressChan
+func (m *Monitor) scanRepositories(ctx context.Context, progressChan chan<- ScanProgress, sc scanner.Scanner) ([]scanner.JobStatus, error) {
 	timeoutCtx, cancel := context.WithTimeout(ctx, DefaultRecentScanTimeout)
 	defer cancel()
 
-	activeRepos, err := m.repoManager.GetActiveRepositories(timeoutCtx, MaxActiveRepositories)
+	repoManager, _ := m.components()
+
+	activeRepos, err := repoManager.GetActiveRepositories(timeoutCtx, MaxActiveRepositories)
 	if err != nil {
 		return nil, err
 	}
 
-	allRepos, err := m.repoManager.GetRepositoriesWithCache(timeoutCtx)
+	allRepos, err := repoManager.GetRepositoriesWithCache(timeoutCtx)
 	if err != nil {
 		return nil, err
 	}
 
 	repoStats := CalculateRepoStats(allRepos)
 
-	m.progressTracker.InitializeProgress(ScanModeRecent, len(allRepos), len(activeRepos), DefaultWorkerPoolSize, repoStats)
+	m.progressTracker.InitializeProgress(ScanModeRecent, len(allRepos), len(activeRepos), m.activeWorkers(len(activeRepos)), repoStats)
 	
 	if progressChan != nil {
 		progressChan <- m.progressTracker.GetProgress()
 	}
 
-	recentWorkerPool := NewWorkerPool(DefaultWorkerPoolSize, m.recentScanner)
+	workerPool := NewWorkerPool(m.workers, sc)
 	
 	progress := m.progressTracker.GetProgress()
-	jobs, err := recentWorkerPool.ScanRepositories(timeoutCtx, activeRepos, progressChan, &progress)
-	if err != nil {
+	jobs, repoErrors, err := workerPool.ScanRepositories(timeoutCtx, activeRepos, progressChan, &progress)
+	// A scan that ran out of time keeps what it found, the rest are repository errors
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
 		return nil, err
 	}
-
-	SortJobsByTime(jobs, true)
+	
+	m.mu.Lock()
+	m.repoErrors = repoErrors
+	m.mu.Unlock()
 
 	m.progressTracker.SetCompleted()
 
 	return jobs, nil
 }
 
+// activeWorkers returns how many workers scan the given number of repositories
+func (m *Monitor) activeWorkers(repos int) int {
+	if repos < m.workers {
+		return repos
+	}
+	return m.workers
+}
+
+// StartMonitoring sends a snapshot of the approval waiting jobs to jobChan on every scan interval
 func (m *Monitor) StartMonitoring(ctx context.Context, jobChan chan<- []scanner.JobStatus) {
+	m.runScanLoop(ctx, jobChan, m.GetPendingJobs)
+}
+
+// StartMonitoringRecent sends a snapshot of the recent jobs, in any status, to jobChan on every scan interval
+func (m *Monitor) StartMonitoringRecent(ctx context.Context, jobChan chan<- []scanner.JobStatus) {
+	m.runScanLoop(ctx, jobChan, m.GetRecentJobs)
+}
+
+// ScanResult is the outcome of one scan started by StartScanning
+type ScanResult struct {
+	Jobs       []scanner.JobStatus // Recent jobs in any status and every waiting job
+	Err        error
+	RepoErrors []RepoError // Repositories whose jobs are missing from Jobs
+	Duration   time.Duration
+	At         time.Time // When the scan completed
+}
+
+// StartScanning scans recent and waiting jobs right away and then on every
+// interval, passing each result to handle, until ctx is cancelled. Unlike
+// StartMonitoring it reports failed scans, so headless consumers can expose them.
+func (m *Monitor) StartScanning(ctx context.Context, handle func(ScanResult)) {
+	go m.startCacheCleanup(ctx)
+	
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	
+	for scanCounter := 1; ; scanCounter++ {
+		start := time.Now()
+		jobs, repoErrors, err := m.scanRecentAndWaiting(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			m.progressTracker.SetScanCompleted()
+		}
+		handle(ScanResult{Jobs: jobs, Err: err, RepoErrors: repoErrors, Duration: time.Since(start), At: time.Now()})
+		
+		m.progressTracker.SetNextScanTimer(time.Now().Add(m.interval), scanCounter, false)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanRecentAndWaiting returns the recent jobs with the waiting jobs merged in,
+// since a run waiting for approval is missing from the recent jobs once newer
+// runs of its repository push it out, or when the GraphQL strategy does not see its commit
+func (m *Monitor) scanRecentAndWaiting(ctx context.Context) ([]scanner.JobStatus, []RepoError, error) {
+	recentJobs, err := m.GetRecentJobs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	repoErrors := m.RepositoryErrors()
+
+	waitingJobs, err := m.GetPendingJobs(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan waiting runs: %w", err)
+	}
+	repoErrors = append(repoErrors, m.RepositoryErrors()...)
+
+	return mergeWaitingJobs(recentJobs, waitingJobs), repoErrors, nil
+}
+
+// mergeWaitingJobs adds the waiting jobs to the recent ones, newest first. A
+// run found by both scans keeps the copy GitHub updated last, the waiting one on a tie.
+func mergeWaitingJobs(recent, waiting []scanner.JobStatus) []scanner.JobStatus {
+	index := make(map[int64]int, len(recent))
+	merged := append([]scanner.JobStatus(nil), recent...)
+	for i, job := range merged {
+		index[job.RunID] = i
+	}
+
+	for _, job := range waiting {
+		i, ok := index[job.RunID]
+		if !ok {
+			merged = append(merged, job)
+			continue
+		}
+		known := merged[i]
+		if known.CompletedAt == nil || job.CompletedAt == nil || !known.CompletedAt.After(*job.CompletedAt) {
+			merged[i] = job
+		}
+	}
+
+	SortJobsByTime(merged, true)
+	return merged
+}
+
+func (m *Monitor) runScanLoop(ctx context.Context, jobChan chan<- []scanner.JobStatus, scan func(ctx context.Context) ([]scanner.JobStatus, error)) {
 	go m.startCacheCleanup(ctx)
 	
 	nextScanAt := time.Now().Add(m.interval)
@@ -197,12 +418,16 @@ func (m *Monitor) StartMonitoring(ctx context.Context, jobChan chan<- []scanner.
 			nextScanAt := time.Now().Add(m.interval)
 			m.progressTracker.SetNextScanTimer(nextScanAt, scanCounter, false)
 			
-			jobs, err := m.GetPendingJobs(ctx)
+			jobs, err := scan(ctx)
 			if err != nil {
 				continue
 			}
 			m.progressTracker.SetScanCompleted()
-			jobChan <- jobs
+			select {
+			case jobChan <- jobs:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"runtime"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v60/github"
@@ -22,6 +23,7 @@ const (
 
 type RepositoryManager struct {
 	client          *ghclient.Client
+	mu              sync.RWMutex
 	cachedRepos     []*github.Repository
 	lastRepoFetch   time.Time
 	repoCacheExpiry time.Duration
@@ -35,9 +37,13 @@ func NewRepositoryManager(client *ghclient.Client) *RepositoryManager {
 }
 
 func (rm *RepositoryManager) GetRepositoriesWithCache(ctx context.Context) ([]*github.Repository, error) {
+	rm.mu.RLock()
 	if len(rm.cachedRepos) > 0 && time.Since(rm.lastRepoFetch) < rm.repoCacheExpiry {
-		return rm.cachedRepos, nil
+		cached := rm.cachedRepos
+		rm.mu.RUnlock()
+		return cached, nil
 	}
+	rm.mu.RUnlock()
 
 	var allRepos []*github.Repository
 	page := 1
@@ -69,12 +75,27 @@ func (rm *RepositoryManager) GetRepositoriesWithCache(ctx context.Context) ([]*g
 		page = resp.NextPage
 	}
 
+	rm.mu.Lock()
 	rm.cachedRepos = allRepos
 	rm.lastRepoFetch = time.Now()
+	rm.mu.Unlock()
 
 	return allRepos, nil
 }
 
+// GetCachedRepositoryNames returns the names of the cached repositories without calling the API
+func (rm *RepositoryManager) GetCachedRepositoryNames() []string {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
+	names := make([]string, 0, len(rm.cachedRepos))
+	for _, repo := range rm.cachedRepos {
+		names = append(names, repo.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (rm *RepositoryManager) FilterRepositories(repos []*github.Repository, filter RepoFilter) []*github.Repository {
 	var filtered []*github.Repository
 	
@@ -209,6 +230,9 @@ func (rm *RepositoryManager) CalculateRepoStats(repos []*github.Repository) (arc
 }
 
 func (rm *RepositoryManager) GetCacheStatus() string {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
 	if len(rm.cachedRepos) == 0 {
 		return "Empty"
 	}
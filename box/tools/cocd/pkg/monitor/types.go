@@ -7,28 +7,28 @@ import (
 
 // ScanProgress represents the progress of repository scanning
 type ScanProgress struct {
-	ActiveWorkers      int
-	TotalRepos         int
-	CompletedRepos     int
-	ScanMode           string // "Smart", "Recent", "Idle"
-	ActiveRepos        int    // Number of repos being scanned (for fast mode)
-	ArchivedRepos      int    // Number of archived repositories
-	DisabledRepos      int    // Number of disabled repositories
-	ValidRepos         int    // Number of valid (non-archived, non-disabled) repositories
-	LimitedRepos       int    // Number of limited repos (capped at 200 for GHES load reduction)
-	CacheStatus        string // Cache status information
-	MemoryUsage        string // Memory usage information
+	ActiveWorkers      int    `json:"active_workers"`
+	TotalRepos         int    `json:"total_repos"`
+	CompletedRepos     int    `json:"completed_repos"`
+	ScanMode           string `json:"scan_mode"`                // "Smart", "Recent", "Idle"
+	ActiveRepos        int    `json:"active_repos"`             // Number of repos being scanned (for fast mode)
+	ArchivedRepos      int    `json:"archived_repos"`           // Number of archived repositories
+	DisabledRepos      int    `json:"disabled_repos"`           // Number of disabled repositories
+	ValidRepos         int    `json:"valid_repos"`              // Number of valid (non-archived, non-disabled) repositories
+	LimitedRepos       int    `json:"limited_repos"`            // Number of limited repos (capped at 200 for GHES load reduction)
+	CacheStatus        string `json:"cache_status,omitempty"`   // Cache status information
+	MemoryUsage        string `json:"memory_usage,omitempty"`   // Memory usage information
 	
 	// Timer information
-	NextScanAt         *time.Time // Next scan scheduled time
-	LastScanAt         *time.Time // Last scan completion time
-	ScanCountdown      int        // Seconds until next scan
-	ScanCycleCount     int        // Current cycle count (1-6)
-	IsNextScanFull     bool       // Whether next scan will be full scan
+	NextScanAt         *time.Time `json:"next_scan_at,omitempty"` // Next scan scheduled time
+	LastScanAt         *time.Time `json:"last_scan_at,omitempty"` // Last scan completion time
+	ScanCountdown      int        `json:"scan_countdown"`         // Seconds until next scan
+	ScanCycleCount     int        `json:"scan_cycle_count"`       // Current cycle count (1-6)
+	IsNextScanFull     bool       `json:"is_next_scan_full"`      // Whether next scan will be full scan
 	
 	// State duration tracking
-	CurrentStateStart  *time.Time // When the current state started
-	StateDuration      int        // Seconds since current state started
+	CurrentStateStart  *time.Time `json:"current_state_start,omitempty"` // When the current state started
+	StateDuration      int        `json:"state_duration"`                // Seconds since current state started
 }
 
 // ScanMode constants
@@ -2,24 +2,26 @@ package monitor
 
 import (
 	"context"
+	"fmt"
 	"sort"
-	"time"
+	"sync"
 
 	"github.com/google/go-github/v60/github"
 	"github.com/younsl/cocd/pkg/scanner"
 )
 
-const (
-	BaseWorkerDelay      = 1000 * time.Millisecond
-	WorkerDelayIncrement = 500 * time.Millisecond
-)
-
+// WorkerPool scans repositories with up to maxWorkers concurrent workers. The
+// request rate is capped by the request budget of the GitHub client the
+// scanner uses, which all workers share.
 type WorkerPool struct {
 	maxWorkers int
 	scanner    scanner.Scanner
 }
 
 func NewWorkerPool(maxWorkers int, sc scanner.Scanner) *WorkerPool {
+	if maxWorkers < 1 {
+		maxWorkers = 1
+	}
 	return &WorkerPool{
 		maxWorkers: maxWorkers,
 		scanner:    sc,
@@ -33,132 +35,195 @@ type JobUpdate struct {
 	Error         error               // Any error that occurred
 }
 
-func (wp *WorkerPool) ScanRepositories(ctx context.Context, repos []*github.Repository, progressChan chan<- ScanProgress, progress *ScanProgress) ([]scanner.JobStatus, error) {
+// RepoError is the failure to scan one repository
+type RepoError struct {
+	Repository string
+	Err        error
+}
+
+func (e RepoError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Repository, e.Err)
+}
+
+func (e RepoError) Unwrap() error {
+	return e.Err
+}
+
+// repoResult is the outcome of scanning the repository at index in the scanned list
+type repoResult struct {
+	index int
+	jobs  []scanner.JobStatus
+	err   error
+}
+
+// scan runs the workers and sends every result to results as soon as it is
+// ready. results is closed once all workers stopped. A BatchScanner gets whole
+// batches of repositories, any other scanner one repository at a time.
+func (wp *WorkerPool) scan(ctx context.Context, repos []*github.Repository, results chan<- repoResult) {
+	batchScanner, batched := wp.scanner.(scanner.BatchScanner)
+	batchSize := 1
+	if batched {
+		batchSize = batchScanner.BatchSize()
+	}
+
+	// Each batch is sent as the index of its first repository
+	starts := make(chan int)
+	go func() {
+		defer close(starts)
+		for start := 0; start < len(repos); start += batchSize {
+			select {
+			case starts <- start:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	workers := wp.maxWorkers
+	if batches := (len(repos) + batchSize - 1) / batchSize; workers > batches {
+		workers = batches
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + batchSize
+				if end > len(repos) {
+					end = len(repos)
+				}
+
+				var batch []repoResult
+				if batched {
+					for i, result := range batchScanner.ScanRepositories(ctx, repos[start:end]) {
+						batch = append(batch, repoResult{index: start + i, jobs: result.Jobs, err: result.Err})
+					}
+				} else {
+					jobs, err := wp.scanner.ScanRepository(ctx, repos[start])
+					batch = append(batch, repoResult{index: start, jobs: jobs, err: err})
+				}
+
+				for _, result := range batch {
+					select {
+					case results <- result:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+}
+
+// ScanRepositories scans repos and returns their jobs in the order of repos,
+// whichever worker finished first. Repositories that failed are returned as
+// RepoErrors next to the jobs of the others. When ctx ends first, the error is
+// set and the jobs of the repositories scanned so far are still returned, with
+// a RepoError for each repository that was not.
+func (wp *WorkerPool) ScanRepositories(ctx context.Context, repos []*github.Repository, progressChan chan<- ScanProgress, progress *ScanProgress) ([]scanner.JobStatus, []RepoError, error) {
 	if len(repos) == 0 {
-		return []scanner.JobStatus{}, nil
+		return []scanner.JobStatus{}, nil, nil
 	}
 
-	var allJobs []scanner.JobStatus
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan repoResult)
+	wp.scan(ctx, repos, results)
+
+	byRepo := make([][]scanner.JobStatus, len(repos))
+	failed := make([]error, len(repos))
+	done := make([]bool, len(repos))
 	completedRepos := 0
-	
-	for _, repo := range repos {
-		select {
-		case <-ctx.Done():
-			return allJobs, ctx.Err()
-		default:
-		}
-		
-		startTime := time.Now()
-		
-		jobs, err := wp.scanner.ScanRepository(ctx, repo)
-		
-		responseTime := time.Since(startTime)
-		
-		if err == nil {
-			allJobs = append(allJobs, jobs...)
-		}
-		
+	for result := range results {
+		byRepo[result.index] = result.jobs
+		failed[result.index] = result.err
+		done[result.index] = true
 		completedRepos++
-		
+
 		if progress != nil {
 			progress.CompletedRepos = completedRepos
-			
+
 			if progressChan != nil {
 				select {
 				case progressChan <- *progress:
 				case <-ctx.Done():
-					return allJobs, ctx.Err()
 				}
 			}
 		}
-		
-		var delay time.Duration
-		if responseTime > 2*time.Second {
-			delay = 3 * time.Second
-		} else if responseTime > 1*time.Second {
-			delay = BaseWorkerDelay
-		} else {
-			delay = BaseWorkerDelay / 2
+	}
+
+	// Checked before cancel runs on return
+	err := ctx.Err()
+
+	var allJobs []scanner.JobStatus
+	var repoErrors []RepoError
+	for i, repo := range repos {
+		if !done[i] {
+			failed[i] = fmt.Errorf("not scanned: %w", err)
 		}
-		
-		if completedRepos < len(repos) {
-			select {
-			case <-ctx.Done():
-				return allJobs, ctx.Err()
-			case <-time.After(delay):
-			}
+		if failed[i] != nil {
+			repoErrors = append(repoErrors, RepoError{Repository: repo.GetName(), Err: failed[i]})
+			continue
 		}
+		allJobs = append(allJobs, byRepo[i]...)
 	}
 
-	return allJobs, nil
+	return allJobs, repoErrors, err
 }
 
-
+// ScanRepositoriesStreamingWithTracker scans repos and sends the jobs of every
+// repository as soon as it is scanned, with the error of the repositories that failed
 func (wp *WorkerPool) ScanRepositoriesStreamingWithTracker(ctx context.Context, repos []*github.Repository, jobUpdateChan chan<- JobUpdate, progressTracker *ProgressTracker) error {
 	if len(repos) == 0 {
 		return nil
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan repoResult)
+	wp.scan(ctx, repos, results)
+
 	completedRepos := 0
-	
-	// Sequential scanning for reduced server load with real-time updates
-	for _, repo := range repos {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		
-		startTime := time.Now()
-		
-		jobs, err := wp.scanner.ScanRepository(ctx, repo)
-		
-		responseTime := time.Since(startTime)
-		
+	for result := range results {
 		completedRepos++
-		
-			if progressTracker != nil {
+
+		if progressTracker != nil {
 			progressTracker.UpdateCompleted(completedRepos)
 		}
-		
+
 		update := JobUpdate{
-			Jobs:          jobs,
-			CompletedRepo: repo.GetName(),
-			Error:         err,
+			Jobs:          result.jobs,
+			CompletedRepo: repos[result.index].GetName(),
+		}
+		if result.err != nil {
+			update.Error = RepoError{Repository: update.CompletedRepo, Err: result.err}
 		}
 		if progressTracker != nil {
 			update.Progress = progressTracker.GetProgress()
 		}
-		
+
 		select {
 		case jobUpdateChan <- update:
 		case <-ctx.Done():
-			return ctx.Err()
-		}
-		
-		var delay time.Duration
-		if responseTime > 2*time.Second {
-			delay = 3 * time.Second
-		} else if responseTime > 1*time.Second {
-			delay = BaseWorkerDelay
-		} else {
-			delay = BaseWorkerDelay / 2
-		}
-		
-		if completedRepos < len(repos) {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(delay):
-			}
 		}
 	}
 
-	return nil
+	return ctx.Err()
 }
 
+// SortJobsByTime sorts jobs by start time. Jobs that started at the same time
+// keep their order, so repeated scans list them the same way.
 func SortJobsByTime(jobs []scanner.JobStatus, newest bool) {
-	sort.Slice(jobs, func(i, j int) bool {
+	sort.SliceStable(jobs, func(i, j int) bool {
 		if jobs[i].StartedAt == nil && jobs[j].StartedAt == nil {
 			return false
 		}
@@ -168,7 +233,7 @@ func SortJobsByTime(jobs []scanner.JobStatus, newest bool) {
 		if jobs[j].StartedAt == nil {
 			return newest
 		}
-		
+
 		if newest {
 			return jobs[i].StartedAt.After(*jobs[j].StartedAt)
 		} else {
@@ -182,4 +247,4 @@ func LimitJobs(jobs []scanner.JobStatus, limit int) []scanner.JobStatus {
 		return jobs
 	}
 	return jobs[:limit]
-}
\ No newline at end of file
+}
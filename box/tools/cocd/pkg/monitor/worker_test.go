@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+// blockingScanner returns one run for the repository named fast right away and
+// blocks the others until the scan is cancelled
+type blockingScanner struct{}
+
+func (blockingScanner) ScanRepository(ctx context.Context, repo *github.Repository) ([]scanner.JobStatus, error) {
+	if repo.GetName() == "fast" {
+		return []scanner.JobStatus{{Repository: "fast", RunID: 1}}, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestScanRepositoriesKeepsPartialResults(t *testing.T) {
+	repos := []*github.Repository{
+		{Name: github.String("slow-1")},
+		{Name: github.String("fast")},
+		{Name: github.String("slow-2")},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The progress of the first finished repository, fast, cancels the scan
+	progressChan := make(chan ScanProgress)
+	go func() {
+		<-progressChan
+		cancel()
+		for range progressChan {
+		}
+	}()
+	defer close(progressChan)
+
+	pool := NewWorkerPool(len(repos), blockingScanner{})
+	jobs, repoErrors, err := pool.ScanRepositories(ctx, repos, progressChan, &ScanProgress{})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+	if len(jobs) != 1 || jobs[0].Repository != "fast" {
+		t.Errorf("jobs = %+v, want the run of fast", jobs)
+	}
+	if len(repoErrors) != 2 || repoErrors[0].Repository != "slow-1" || repoErrors[1].Repository != "slow-2" {
+		t.Fatalf("repository errors = %v, want slow-1 and slow-2", repoErrors)
+	}
+	for _, repoError := range repoErrors {
+		if !errors.Is(repoError, context.Canceled) {
+			t.Errorf("%v does not wrap %v", repoError, context.Canceled)
+		}
+	}
+}
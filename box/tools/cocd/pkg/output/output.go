@@ -0,0 +1,138 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/cocd/pkg/scanner"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a machine or human readable job list format
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatCSV   Format = "csv"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatCSV}
+
+// jobColumns are the columns of the table and CSV formats
+var jobColumns = []string{
+	"repository", "workflow_name", "name", "run_id", "run_number", "status",
+	"conclusion", "environment", "branch", "event", "actor", "started_at", "completed_at",
+}
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == strings.ToLower(name) {
+			return format, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return "", fmt.Errorf("unknown output format %q (use one of %s)", name, strings.Join(names, ", "))
+}
+
+// WriteJobs writes jobs to w in the given format
+func WriteJobs(w io.Writer, jobs []scanner.JobStatus, format Format) error {
+	// Encode an empty list rather than null
+	if jobs == nil {
+		jobs = []scanner.JobStatus{}
+	}
+
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(jobs)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(jobs); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatCSV:
+		return writeCSV(w, jobs)
+	case FormatTable:
+		return writeTable(w, jobs)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func writeCSV(w io.Writer, jobs []scanner.JobStatus) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(jobColumns); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := writer.Write(jobRow(job, "")); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeTable(w io.Writer, jobs []scanner.JobStatus) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(jobColumns))
+	for i, column := range jobColumns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+
+	for _, job := range jobs {
+		fmt.Fprintln(writer, strings.Join(jobRow(job, "-"), "\t"))
+	}
+	return writer.Flush()
+}
+
+// jobRow returns the values of jobColumns for a job, using empty for missing values
+func jobRow(job scanner.JobStatus, empty string) []string {
+	text := func(value string) string {
+		if value == "" {
+			return empty
+		}
+		return value
+	}
+	timestamp := func(value *time.Time) string {
+		if value == nil {
+			return empty
+		}
+		return value.UTC().Format(time.RFC3339)
+	}
+
+	return []string{
+		job.Repository,
+		text(job.WorkflowName),
+		text(job.Name),
+		strconv.FormatInt(job.RunID, 10),
+		strconv.Itoa(job.RunNumber),
+		text(job.Status),
+		text(job.Conclusion),
+		text(job.Environment),
+		text(job.Branch),
+		text(job.Event),
+		text(job.Actor),
+		timestamp(job.StartedAt),
+		timestamp(job.CompletedAt),
+	}
+}
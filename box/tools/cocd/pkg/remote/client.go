@@ -0,0 +1,181 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ghclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/scanner"
+	"github.com/younsl/cocd/pkg/server"
+)
+
+// requestTimeout bounds the JSON requests to the server. Event streams are not bounded.
+const requestTimeout = 30 * time.Second
+
+// Client reads the jobs of a cocd server started with "cocd serve --listen"
+// instead of scanning GitHub itself
+type Client struct {
+	serverURL *url.URL
+	http      *http.Client
+	github    *ghclient.Client
+	token     string
+	info      server.InfoResponse
+}
+
+// NewClient connects to a cocd server. token is the caller's GitHub token, which
+// the server checks against the organization and passes on to the GitHub API
+// calls it forwards, such as approvals.
+func NewClient(ctx context.Context, serverURL, token string) (*Client, error) {
+	parsedURL, err := url.Parse(strings.TrimSuffix(serverURL, "/"))
+	if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q", serverURL)
+	}
+
+	c := &Client{
+		serverURL: parsedURL,
+		http:      &http.Client{},
+		token:     token,
+	}
+
+	if err := c.get(ctx, "/api/v1/info", &c.info); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", serverURL, err)
+	}
+
+	c.github, err = ghclient.NewClient(token, parsedURL.String()+server.GitHubProxyPrefix, c.info.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Info returns what the server reported about itself when the client connected
+func (c *Client) Info() server.InfoResponse {
+	return c.info
+}
+
+// GitHub returns a GitHub client whose requests are forwarded by the server
+func (c *Client) GitHub() *ghclient.Client {
+	return c.github
+}
+
+// PendingJobs returns the jobs waiting for approval found by the server's last scan
+func (c *Client) PendingJobs(ctx context.Context) ([]scanner.JobStatus, error) {
+	var response server.JobsResponse
+	if err := c.get(ctx, "/api/v1/jobs/pending", &response); err != nil {
+		return nil, err
+	}
+	return response.Jobs, nil
+}
+
+// RecentJobs returns the jobs in any status found by the server's last scan
+func (c *Client) RecentJobs(ctx context.Context) ([]scanner.JobStatus, error) {
+	var response server.JobsResponse
+	if err := c.get(ctx, "/api/v1/jobs/recent", &response); err != nil {
+		return nil, err
+	}
+	return response.Jobs, nil
+}
+
+// Progress returns the progress of the server's current scan
+func (c *Client) Progress(ctx context.Context) (server.ProgressResponse, error) {
+	var response server.ProgressResponse
+	err := c.get(ctx, "/api/v1/progress", &response)
+	return response, err
+}
+
+// Events calls handle for every run state change the server streams, until the
+// context is done or the server ends the stream
+func (c *Client) Events(ctx context.Context, handle func(monitor.Event)) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint("/api/v1/events"), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "text/event-stream")
+	c.authorize(request)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to subscribe to events: %s", response.Status)
+	}
+
+	// Only the data lines matter, the event name is repeated in the payload
+	var data strings.Builder
+	lines := bufio.NewScanner(response.Body)
+	lines.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lines.Scan() {
+		line := lines.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				var event monitor.Event
+				if err := json.Unmarshal([]byte(data.String()), &event); err == nil {
+					handle(event)
+				}
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+
+	if err := lines.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("event stream interrupted: %w", err)
+	}
+	return ctx.Err()
+}
+
+// get decodes the JSON response of an API endpoint into v
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint(path), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	c.authorize(request)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var failure server.ErrorResponse
+		if json.NewDecoder(response.Body).Decode(&failure) == nil && failure.Error != "" {
+			return fmt.Errorf("cocd server: %s", failure.Error)
+		}
+		return fmt.Errorf("cocd server: %s", response.Status)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response from cocd server: %w", err)
+	}
+	return nil
+}
+
+// authorize sends the caller's GitHub token, which the server requires on every endpoint
+func (c *Client) authorize(request *http.Request) {
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
+func (c *Client) endpoint(path string) string {
+	return c.serverURL.String() + path
+}
@@ -0,0 +1,138 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// filterFields maps the field names accepted in field:value terms to job values
+var filterFields = map[string]func(JobStatus) string{
+	"repo":       func(js JobStatus) string { return js.Repository },
+	"repository": func(js JobStatus) string { return js.Repository },
+	"workflow":   func(js JobStatus) string { return js.WorkflowName },
+	"branch":     func(js JobStatus) string { return js.Branch },
+	"actor":      func(js JobStatus) string { return js.Actor },
+	"event":      func(js JobStatus) string { return js.Event },
+	"status":     func(js JobStatus) string { return js.Status },
+}
+
+// filterFieldOrder is the order of fields searched by terms without a field prefix
+var filterFieldOrder = []string{"repo", "workflow", "branch", "actor", "event", "status"}
+
+// filterTerm is a single whitespace-separated term of a filter query
+type filterTerm struct {
+	field     string // Empty to match any field
+	substring string // Lowercased substring, used when pattern is nil
+	pattern   *regexp.Regexp
+}
+
+// Filter narrows a job list down to the jobs matching every term of a query.
+// Terms are case-insensitive substrings, /regex/ patterns, or either of those
+// prefixed with a field name such as branch:main or actor:/^bot-/.
+type Filter struct {
+	query string
+	terms []filterTerm
+}
+
+// ParseFilter parses a filter query. An empty query returns a nil filter that matches everything.
+func ParseFilter(query string) (*Filter, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+
+	filter := &Filter{query: query}
+	for _, token := range strings.Fields(query) {
+		term, err := parseFilterTerm(token)
+		if err != nil {
+			return nil, err
+		}
+		filter.terms = append(filter.terms, term)
+	}
+
+	return filter, nil
+}
+
+func parseFilterTerm(token string) (filterTerm, error) {
+	var term filterTerm
+
+	// A colon inside a regex is part of the pattern, not a field separator
+	if name, value, found := strings.Cut(token, ":"); found && !strings.HasPrefix(token, "/") {
+		name = strings.ToLower(name)
+		if _, ok := filterFields[name]; !ok {
+			return term, fmt.Errorf("unknown filter field %q (use one of %s)", name, strings.Join(filterFieldOrder, ", "))
+		}
+		term.field = name
+		token = value
+	}
+
+	if len(token) >= 2 && strings.HasPrefix(token, "/") && strings.HasSuffix(token, "/") {
+		pattern, err := regexp.Compile("(?i)" + token[1:len(token)-1])
+		if err != nil {
+			return term, fmt.Errorf("invalid regex %s: %w", token, err)
+		}
+		term.pattern = pattern
+		return term, nil
+	}
+
+	term.substring = strings.ToLower(token)
+	return term, nil
+}
+
+// String returns the query the filter was parsed from
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.query
+}
+
+// Match returns whether a job matches every term of the filter
+func (f *Filter) Match(job JobStatus) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, term := range f.terms {
+		if !term.match(job) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the jobs matching the filter, keeping their order
+func (f *Filter) Apply(jobs []JobStatus) []JobStatus {
+	if f == nil {
+		return jobs
+	}
+
+	filtered := make([]JobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		if f.Match(job) {
+			filtered = append(filtered, job)
+		}
+	}
+	return filtered
+}
+
+func (t filterTerm) match(job JobStatus) bool {
+	if t.field != "" {
+		return t.matchValue(filterFields[t.field](job))
+	}
+
+	for _, name := range filterFieldOrder {
+		if t.matchValue(filterFields[name](job)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t filterTerm) matchValue(value string) bool {
+	if t.pattern != nil {
+		return t.pattern.MatchString(value)
+	}
+	return strings.Contains(strings.ToLower(value), t.substring)
+}
@@ -0,0 +1,244 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	ghclient "github.com/younsl/cocd/pkg/github"
+)
+
+const (
+	// graphQLBatchSize is how many repositories one GraphQL query reads
+	graphQLBatchSize = 25
+	// graphQLBranches is how many of the most recently committed branches of a repository are read
+	graphQLBranches = 10
+	// graphQLSuitesPerCommit is how many check suites are read from the head commit of a branch
+	graphQLSuitesPerCommit = 5
+)
+
+// graphQLRecentRuns reads the workflow runs of the head commits of the latest
+// branches. The workflow runs API has no GraphQL equivalent, but every run
+// belongs to a check suite of the commit it ran on.
+var graphQLRecentRuns = fmt.Sprintf(`
+fragment recentRuns on Repository {
+  refs(refPrefix: "refs/heads/", first: %d, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+    nodes {
+      name
+      target {
+        ... on Commit {
+          checkSuites(last: %d) {
+            nodes {
+              status
+              conclusion
+              branch { name }
+              creator { login }
+              workflowRun {
+                databaseId
+                runNumber
+                event
+                createdAt
+                updatedAt
+                workflow { name }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`, graphQLBranches, graphQLSuitesPerCommit)
+
+type graphQLRepository struct {
+	Refs struct {
+		Nodes []struct {
+			Name   string `json:"name"`
+			Target struct {
+				CheckSuites struct {
+					Nodes []graphQLCheckSuite `json:"nodes"`
+				} `json:"checkSuites"`
+			} `json:"target"`
+		} `json:"nodes"`
+	} `json:"refs"`
+}
+
+type graphQLCheckSuite struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	Branch     *struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Creator *struct {
+		Login string `json:"login"`
+	} `json:"creator"`
+	WorkflowRun *struct {
+		DatabaseID int64     `json:"databaseId"`
+		RunNumber  int       `json:"runNumber"`
+		Event      string    `json:"event"`
+		CreatedAt  time.Time `json:"createdAt"`
+		UpdatedAt  time.Time `json:"updatedAt"`
+		Workflow   struct {
+			Name string `json:"name"`
+		} `json:"workflow"`
+	} `json:"workflowRun"`
+}
+
+// GraphQLJobsScanner finds the recent workflow runs of many repositories with
+// one GraphQL query per batch instead of one REST request per repository. It
+// sees the runs on the head commits of the latest branches, so runs of older
+// commits and of pull requests from forks are missed.
+type GraphQLJobsScanner struct {
+	client *ghclient.Client
+	// rest reads the pending deployments of waiting runs, which only REST offers
+	rest *RecentJobsScanner
+}
+
+func NewGraphQLJobsScanner(client *ghclient.Client) *GraphQLJobsScanner {
+	return &GraphQLJobsScanner{
+		client: client,
+		rest:   NewRecentJobsScanner(client),
+	}
+}
+
+// BatchSize returns how many repositories are read with one query
+func (s *GraphQLJobsScanner) BatchSize() int {
+	return graphQLBatchSize
+}
+
+func (s *GraphQLJobsScanner) ScanRepository(ctx context.Context, repo *github.Repository) ([]JobStatus, error) {
+	result := s.ScanRepositories(ctx, []*github.Repository{repo})[0]
+	return result.Jobs, result.Err
+}
+
+// ScanRepositories reads the recent runs of repos with a single query.
+// Archived and disabled repositories are skipped like RecentJobsScanner does.
+func (s *GraphQLJobsScanner) ScanRepositories(ctx context.Context, repos []*github.Repository) []RepoScanResult {
+	results := make([]RepoScanResult, len(repos))
+
+	var query strings.Builder
+	query.WriteString("query($owner: String!")
+	var fields strings.Builder
+	variables := map[string]interface{}{"owner": s.client.GetOrganization()}
+	aliases := make(map[string]int)
+	for i, repo := range repos {
+		if repo.GetArchived() || repo.GetDisabled() {
+			continue
+		}
+		alias := fmt.Sprintf("r%d", i)
+		aliases[alias] = i
+		variables[alias] = repo.GetName()
+		fmt.Fprintf(&query, ", $%s: String!", alias)
+		fmt.Fprintf(&fields, "  %s: repository(owner: $owner, name: $%s) { ...recentRuns }\n", alias, alias)
+	}
+	if len(aliases) == 0 {
+		return results
+	}
+	query.WriteString(") {\n")
+	query.WriteString(fields.String())
+	query.WriteString("}\n")
+	query.WriteString(graphQLRecentRuns)
+
+	var data map[string]*graphQLRepository
+	err := s.client.GraphQL(ctx, query.String(), variables, &data)
+
+	// Errors of a single repository, such as one that was deleted, leave the others usable
+	failed := make(map[int]error)
+	if graphQLErrors, ok := err.(ghclient.GraphQLErrors); ok {
+		err = nil
+		for _, graphQLError := range graphQLErrors {
+			index, ok := aliases[graphQLAlias(graphQLError)]
+			if !ok {
+				err = graphQLErrors
+				break
+			}
+			failed[index] = graphQLError
+		}
+	}
+
+	for alias, i := range aliases {
+		switch {
+		case err != nil:
+			results[i].Err = err
+		case failed[i] != nil:
+			results[i].Err = failed[i]
+		case data[alias] == nil:
+			results[i].Err = fmt.Errorf("repository %s not found", repos[i].GetName())
+		default:
+			results[i].Jobs = s.recentJobs(ctx, repos[i].GetName(), data[alias])
+		}
+	}
+	return results
+}
+
+// recentJobs converts the check suites of a repository into its latest runs, newest first
+func (s *GraphQLJobsScanner) recentJobs(ctx context.Context, repo string, repository *graphQLRepository) []JobStatus {
+	// A commit on several branches has the same runs on each of them
+	seen := make(map[int64]bool)
+	var jobs []JobStatus
+	for _, ref := range repository.Refs.Nodes {
+		for _, suite := range ref.Target.CheckSuites.Nodes {
+			run := suite.WorkflowRun
+			if run == nil || seen[run.DatabaseID] {
+				continue
+			}
+			seen[run.DatabaseID] = true
+
+			status := strings.ToLower(suite.Status)
+			conclusion := strings.ToLower(suite.Conclusion)
+			if status == "completed" && conclusion != "" {
+				status = conclusion
+			}
+			branch := ref.Name
+			if suite.Branch != nil {
+				branch = suite.Branch.Name
+			}
+			var actor string
+			if suite.Creator != nil {
+				actor = suite.Creator.Login
+			}
+			createdAt, updatedAt := run.CreatedAt, run.UpdatedAt
+
+			jobs = append(jobs, JobStatus{
+				ID:           run.DatabaseID,
+				Name:         run.Workflow.Name,
+				RunID:        run.DatabaseID,
+				RunNumber:    run.RunNumber,
+				Status:       status,
+				Conclusion:   conclusion,
+				StartedAt:    &createdAt,
+				CompletedAt:  &updatedAt,
+				WorkflowName: run.Workflow.Name,
+				Branch:       branch,
+				Event:        run.Event,
+				Actor:        actor,
+				Repository:   repo,
+			})
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.After(*jobs[j].StartedAt)
+	})
+	if len(jobs) > recentRunsPerRepository {
+		jobs = jobs[:recentRunsPerRepository]
+	}
+
+	for i := range jobs {
+		if jobs[i].Status == "waiting" {
+			s.rest.addReviewDetails(ctx, &jobs[i])
+		}
+	}
+	return jobs
+}
+
+// graphQLAlias returns the alias of the repository field an error belongs to
+func graphQLAlias(err ghclient.GraphQLError) string {
+	if len(err.Path) == 0 {
+		return ""
+	}
+	alias, _ := err.Path[0].(string)
+	return alias
+}
@@ -2,23 +2,84 @@ package scanner
 
 import (
 	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v60/github"
 	ghclient "github.com/younsl/cocd/pkg/github"
 )
 
+const (
+	// recentRunsPerRepository is how many of the latest workflow runs of a repository are scanned
+	recentRunsPerRepository = 10
+	// fullScanInterval is how often a repository is scanned without its cursor. Re-runs
+	// of older runs keep their creation time, so only a full scan notices them.
+	fullScanInterval = 10 * time.Minute
+	// maxWaitingRunsPerRepository is the page size of the waiting runs query
+	maxWaitingRunsPerRepository = 100
+	// incrementalRunsPerPage is the page size of the query for the runs created since a cursor
+	incrementalRunsPerPage = 100
+	// maxIncrementalPages bounds the pages read for the runs created since a cursor
+	maxIncrementalPages = 10
+)
+
 // Scanner interface for different scanning strategies
 type Scanner interface {
 	ScanRepository(ctx context.Context, repo *github.Repository) ([]JobStatus, error)
 }
 
+// BatchScanner is a Scanner that reads many repositories with a single request
+type BatchScanner interface {
+	Scanner
+	// BatchSize is the most repositories ScanRepositories reads at once
+	BatchSize() int
+	// ScanRepositories returns one result per repository, in the order of repos
+	ScanRepositories(ctx context.Context, repos []*github.Repository) []RepoScanResult
+}
+
+// RecentJobsScanner finds the latest runs of a repository. After the first scan
+// it only asks for the runs created since the oldest run that had not finished,
+// or since the newest run when all had, and merges them into the runs it knows.
+// The cursors are kept in memory only, so the first scan of a process is full.
 type RecentJobsScanner struct {
 	client *ghclient.Client
+	
+	mu      sync.Mutex
+	cursors map[string]*repoCursor
+}
+
+// repoCursor is what the previous scans found in a repository
+type repoCursor struct {
+	jobs       []JobStatus // Latest runs, newest first
+	fullScanAt time.Time
+}
+
+// since returns the creation time from which runs may have changed since the
+// cursor was saved, zero when the repository has to be scanned in full
+func (c *repoCursor) since(now time.Time) time.Time {
+	if now.Sub(c.fullScanAt) >= fullScanInterval || len(c.jobs) == 0 {
+		return time.Time{}
+	}
+	
+	newest := c.jobs[0].StartedAt
+	if newest == nil {
+		return time.Time{}
+	}
+	since := *newest
+	for _, job := range c.jobs {
+		if job.Conclusion == "" && job.StartedAt != nil && job.StartedAt.Before(since) {
+			since = *job.StartedAt
+		}
+	}
+	return since
 }
 
 func NewRecentJobsScanner(client *ghclient.Client) *RecentJobsScanner {
 	return &RecentJobsScanner{
-		client: client,
+		client:  client,
+		cursors: make(map[string]*repoCursor),
 	}
 }
 
@@ -31,7 +92,130 @@ func (s *RecentJobsScanner) ScanRepository(ctx context.Context, repo *github.Rep
 
 	opts := &github.ListWorkflowRunsOptions{
 		ListOptions: github.ListOptions{
-			PerPage: 10,
+			PerPage: recentRunsPerRepository,
+		},
+	}
+
+	now := time.Now()
+	cursor := s.cursor(repo.GetName())
+	var since time.Time
+	if cursor != nil {
+		since = cursor.since(now)
+	}
+	if !since.IsZero() {
+		// Every run created since the cursor is read, so that none of the
+		// unfinished runs it knows keeps a stale status behind a full page
+		opts.Created = ">=" + since.UTC().Format(time.RFC3339)
+		opts.PerPage = incrementalRunsPerPage
+	}
+
+	truncated := false
+	for page := 1; ; page++ {
+		runs, response, err := s.client.ListWorkflowRuns(ctx, repo.GetName(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, run := range runs.WorkflowRuns {
+			recentJobs = append(recentJobs, s.ScanRun(ctx, repo.GetName(), run))
+		}
+
+		if since.IsZero() || response.NextPage == 0 {
+			break
+		}
+		if page >= maxIncrementalPages {
+			truncated = true
+			break
+		}
+		opts.Page = response.NextPage
+	}
+
+	var next *repoCursor
+	switch {
+	case since.IsZero():
+		next = &repoCursor{jobs: recentJobs, fullScanAt: now}
+	case truncated:
+		// The known runs older than the pages read may be stale. The runs read
+		// are the newest ones, so they are kept alone and the next scan is full.
+		next = &repoCursor{jobs: mergeJobs(recentJobs, nil)}
+	default:
+		next = &repoCursor{jobs: mergeJobs(recentJobs, cursor.jobs), fullScanAt: cursor.fullScanAt}
+	}
+	s.mu.Lock()
+	s.cursors[repo.GetName()] = next
+	s.mu.Unlock()
+
+	return append([]JobStatus(nil), next.jobs...), nil
+}
+
+func (s *RecentJobsScanner) cursor(repo string) *repoCursor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursors[repo]
+}
+
+// mergeJobs adds the known runs that were not fetched again to the fetched ones
+// and keeps the latest recentRunsPerRepository of them, newest first
+func mergeJobs(fetched, known []JobStatus) []JobStatus {
+	merged := append([]JobStatus(nil), fetched...)
+	seen := make(map[int64]bool, len(fetched))
+	for _, job := range fetched {
+		seen[job.RunID] = true
+	}
+	for _, job := range known {
+		if !seen[job.RunID] {
+			merged = append(merged, job)
+		}
+	}
+	
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].StartedAt == nil || merged[j].StartedAt == nil {
+			return merged[j].StartedAt == nil && merged[i].StartedAt != nil
+		}
+		return merged[i].StartedAt.After(*merged[j].StartedAt)
+	})
+	if len(merged) > recentRunsPerRepository {
+		merged = merged[:recentRunsPerRepository]
+	}
+	return merged
+}
+
+// ScanRun converts a single workflow run, with the review details of a waiting run
+func (s *RecentJobsScanner) ScanRun(ctx context.Context, repo string, run *github.WorkflowRun) JobStatus {
+	job := NewJobStatus(repo, run)
+	
+	if run.GetStatus() == "waiting" {
+		s.addReviewDetails(ctx, &job)
+	}
+	
+	return job
+}
+
+// WaitingJobsScanner finds the runs waiting for a deployment approval by asking
+// GitHub for runs with status=waiting, so waiting runs older than the latest
+// runs are found too
+type WaitingJobsScanner struct {
+	client *ghclient.Client
+	// rest reads the review details of the waiting runs
+	rest *RecentJobsScanner
+}
+
+func NewWaitingJobsScanner(client *ghclient.Client) *WaitingJobsScanner {
+	return &WaitingJobsScanner{
+		client: client,
+		rest:   NewRecentJobsScanner(client),
+	}
+}
+
+func (s *WaitingJobsScanner) ScanRepository(ctx context.Context, repo *github.Repository) ([]JobStatus, error) {
+	if repo.GetArchived() || repo.GetDisabled() {
+		return nil, nil
+	}
+
+	opts := &github.ListWorkflowRunsOptions{
+		Status: "waiting",
+		ListOptions: github.ListOptions{
+			PerPage: maxWaitingRunsPerRepository,
 		},
 	}
 
@@ -40,32 +224,65 @@ func (s *RecentJobsScanner) ScanRepository(ctx context.Context, repo *github.Rep
 		return nil, err
 	}
 
+	var waitingJobs []JobStatus
 	for _, run := range runs.WorkflowRuns {
-		status := run.GetStatus()
-		conclusion := run.GetConclusion()
-		
-		displayStatus := status
-		if status == "completed" && conclusion != "" {
-			displayStatus = conclusion
+		waitingJobs = append(waitingJobs, s.rest.ScanRun(ctx, repo.GetName(), run))
+	}
+	return waitingJobs, nil
+}
+
+// NewJobStatus converts a workflow run into a JobStatus. Completed runs show their conclusion as status.
+func NewJobStatus(repo string, run *github.WorkflowRun) JobStatus {
+	status := run.GetStatus()
+	conclusion := run.GetConclusion()
+	
+	displayStatus := status
+	if status == "completed" && conclusion != "" {
+		displayStatus = conclusion
+	}
+	
+	return JobStatus{
+		ID:           run.GetID(),
+		Name:         run.GetName(),
+		RunID:        run.GetID(),
+		RunNumber:    run.GetRunNumber(),
+		Status:       displayStatus,
+		Conclusion:   conclusion,
+		StartedAt:    run.CreatedAt.GetTime(),
+		CompletedAt:  run.UpdatedAt.GetTime(),
+		Environment:  "",
+		WorkflowName: run.GetName(),
+		Branch:       run.GetHeadBranch(),
+		Event:        run.GetEvent(),
+		Actor:        run.GetActor().GetLogin(),
+		Repository:   repo,
+	}
+}
+
+// addReviewDetails fills in the pending environments, approvability and required
+// reviewers of a waiting run. Failures leave ReviewChecked unset so the run is
+// still treated as approvable.
+func (s *RecentJobsScanner) addReviewDetails(ctx context.Context, job *JobStatus) {
+	pendingDeployments, _, err := s.client.GetPendingDeployments(ctx, job.Repository, job.RunID)
+	if err != nil {
+		return
+	}
+
+	var environments []string
+	seenReviewers := make(map[string]bool)
+	for _, pd := range pendingDeployments {
+		environments = append(environments, pd.EnvironmentName())
+		if pd.CurrentUserCanApprove {
+			job.CanApprove = true
+		}
+		for _, name := range pd.ReviewerNames() {
+			if !seenReviewers[name] {
+				seenReviewers[name] = true
+				job.Reviewers = append(job.Reviewers, name)
+			}
 		}
-		
-		recentJobs = append(recentJobs, JobStatus{
-			ID:           run.GetID(),
-			Name:         run.GetName(),
-			RunID:        run.GetID(),
-			RunNumber:    run.GetRunNumber(),
-			Status:       displayStatus,
-			Conclusion:   conclusion,
-			StartedAt:    run.CreatedAt.GetTime(),
-			CompletedAt:  run.UpdatedAt.GetTime(),
-			Environment:  "",
-			WorkflowName: run.GetName(),
-			Branch:       run.GetHeadBranch(),
-			Event:        run.GetEvent(),
-			Actor:        run.GetActor().GetLogin(),
-			Repository:   repo.GetName(),
-		})
-	}
-
-	return recentJobs, nil
+	}
+
+	job.Environment = strings.Join(environments, ", ")
+	job.ReviewChecked = true
 }
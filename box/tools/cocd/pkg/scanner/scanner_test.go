@@ -0,0 +1,202 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	ghclient "github.com/younsl/cocd/pkg/github"
+)
+
+func TestRepoCursorSince(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	at := func(minutesAgo int) *time.Time {
+		started := now.Add(-time.Duration(minutesAgo) * time.Minute)
+		return &started
+	}
+
+	tests := []struct {
+		name   string
+		cursor repoCursor
+		want   time.Time
+	}{
+		{
+			name:   "no runs",
+			cursor: repoCursor{fullScanAt: now},
+			want:   time.Time{},
+		},
+		{
+			name: "full scan due",
+			cursor: repoCursor{
+				jobs:       []JobStatus{{RunID: 1, StartedAt: at(1), Conclusion: "success"}},
+				fullScanAt: now.Add(-fullScanInterval),
+			},
+			want: time.Time{},
+		},
+		{
+			name: "cut short by the page cap",
+			cursor: repoCursor{
+				jobs: []JobStatus{{RunID: 1, StartedAt: at(1), Status: "waiting"}},
+			},
+			want: time.Time{},
+		},
+		{
+			name: "newest run without a start",
+			cursor: repoCursor{
+				jobs:       []JobStatus{{RunID: 1}},
+				fullScanAt: now,
+			},
+			want: time.Time{},
+		},
+		{
+			name: "all runs finished",
+			cursor: repoCursor{
+				jobs: []JobStatus{
+					{RunID: 2, StartedAt: at(1), Conclusion: "success"},
+					{RunID: 1, StartedAt: at(5), Conclusion: "failure"},
+				},
+				fullScanAt: now,
+			},
+			want: *at(1),
+		},
+		{
+			name: "oldest unfinished run",
+			cursor: repoCursor{
+				jobs: []JobStatus{
+					{RunID: 3, StartedAt: at(1), Conclusion: "success"},
+					{RunID: 2, StartedAt: at(5), Status: "in_progress"},
+					{RunID: 1, StartedAt: at(30), Status: "waiting"},
+				},
+				fullScanAt: now.Add(-time.Minute),
+			},
+			want: *at(30),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cursor.since(now); !got.Equal(tt.want) {
+				t.Errorf("since() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeJobs(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	run := func(id int64, status string) JobStatus {
+		started := now.Add(time.Duration(id) * time.Minute)
+		return JobStatus{RunID: id, Status: status, StartedAt: &started}
+	}
+	ids := func(jobs []JobStatus) []int64 {
+		var result []int64
+		for _, job := range jobs {
+			result = append(result, job.RunID)
+		}
+		return result
+	}
+
+	t.Run("fetched runs replace known ones", func(t *testing.T) {
+		merged := mergeJobs(
+			[]JobStatus{run(3, "queued"), run(2, "completed")},
+			[]JobStatus{run(2, "waiting"), run(1, "completed")},
+		)
+		if got, want := ids(merged), []int64{3, 2, 1}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("run IDs = %v, want %v", got, want)
+		}
+		if merged[1].Status != "completed" {
+			t.Errorf("run 2 status = %q, want the fetched %q", merged[1].Status, "completed")
+		}
+	})
+
+	t.Run("newest first", func(t *testing.T) {
+		merged := mergeJobs([]JobStatus{run(2, ""), run(5, "")}, []JobStatus{run(4, ""), run(1, "")})
+		if got, want := ids(merged), []int64{5, 4, 2, 1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("run IDs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("runs without a start last", func(t *testing.T) {
+		merged := mergeJobs([]JobStatus{{RunID: 9}, run(1, "")}, []JobStatus{run(2, "")})
+		if got, want := ids(merged), []int64{2, 1, 9}; !reflect.DeepEqual(got, want) {
+			t.Errorf("run IDs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("keeps the latest runs", func(t *testing.T) {
+		var fetched, known []JobStatus
+		for id := int64(20); id > 12; id-- {
+			fetched = append(fetched, run(id, ""))
+		}
+		for id := int64(12); id > 0; id-- {
+			known = append(known, run(id, ""))
+		}
+		merged := mergeJobs(fetched, known)
+		if len(merged) != recentRunsPerRepository {
+			t.Fatalf("len = %d, want %d", len(merged), recentRunsPerRepository)
+		}
+		if merged[0].RunID != 20 || merged[len(merged)-1].RunID != 11 {
+			t.Errorf("run IDs = %v, want 20 down to 11", ids(merged))
+		}
+	})
+}
+
+func TestScanRepositoryForcesFullScanAfterPageCap(t *testing.T) {
+	now := time.Now().UTC()
+	var createdQueries []string
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created := r.URL.Query().Get("created")
+		createdQueries = append(createdQueries, created)
+
+		// An unfinished run keeps the cursor on it, and every incremental page has a next one
+		var runs []map[string]interface{}
+		for i := 0; i < recentRunsPerRepository; i++ {
+			status := "completed"
+			if i == recentRunsPerRepository-1 {
+				status = "in_progress"
+			}
+			at := now.Add(-time.Duration(i) * time.Minute).Format(time.RFC3339)
+			runs = append(runs, map[string]interface{}{"id": 1000 - i, "status": status, "created_at": at, "updated_at": at})
+		}
+		if created != "" {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, "http://"+r.Host, r.URL.Path, page+1))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total_count": len(runs), "workflow_runs": runs})
+	}))
+	defer gh.Close()
+
+	client, err := ghclient.NewClient("token", gh.URL, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewRecentJobsScanner(client)
+	repo := &github.Repository{Name: github.String("app")}
+
+	for scan := 0; scan < 3; scan++ {
+		if _, err := s.ScanRepository(context.Background(), repo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A full scan, an incremental one cut short after maxIncrementalPages, then a full one again
+	if len(createdQueries) != 1+maxIncrementalPages+1 {
+		t.Fatalf("requests = %d, want %d", len(createdQueries), 1+maxIncrementalPages+1)
+	}
+	if createdQueries[0] != "" || createdQueries[len(createdQueries)-1] != "" {
+		t.Errorf("created filters = %q, want the first and last scans to be full", createdQueries)
+	}
+	for _, created := range createdQueries[1 : 1+maxIncrementalPages] {
+		if created == "" {
+			t.Errorf("created filters = %q, want the second scan to be incremental", createdQueries)
+			break
+		}
+	}
+}
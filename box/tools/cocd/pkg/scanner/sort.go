@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortKey identifies the job table column jobs are ordered by
+type SortKey string
+
+const (
+	SortByDefault    SortKey = "" // Order produced by the monitor
+	SortByRepository SortKey = "repository"
+	SortByWorkflow   SortKey = "workflow"
+	SortByStatus     SortKey = "status"
+	SortByBranch     SortKey = "branch"
+	SortByActor      SortKey = "actor"
+	SortByAge        SortKey = "age"
+	SortByRunNumber  SortKey = "run number"
+)
+
+// SortKeys lists the sort keys in the order they are cycled through
+var SortKeys = []SortKey{
+	SortByDefault,
+	SortByRepository,
+	SortByWorkflow,
+	SortByStatus,
+	SortByBranch,
+	SortByActor,
+	SortByAge,
+	SortByRunNumber,
+}
+
+// SortJobs orders jobs in place by key. Ties keep their existing order.
+// Ascending age lists the most recently started jobs first.
+func SortJobs(jobs []JobStatus, key SortKey, descending bool) {
+	less := jobLess(key)
+	if less == nil {
+		return
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if descending {
+			return less(jobs[j], jobs[i])
+		}
+		return less(jobs[i], jobs[j])
+	})
+}
+
+func jobLess(key SortKey) func(a, b JobStatus) bool {
+	compareText := func(value func(JobStatus) string) func(a, b JobStatus) bool {
+		return func(a, b JobStatus) bool {
+			return strings.ToLower(value(a)) < strings.ToLower(value(b))
+		}
+	}
+
+	switch key {
+	case SortByRepository:
+		return compareText(func(js JobStatus) string { return js.Repository })
+	case SortByWorkflow:
+		return compareText(func(js JobStatus) string { return js.Name })
+	case SortByStatus:
+		return compareText(func(js JobStatus) string { return js.Status })
+	case SortByBranch:
+		return compareText(func(js JobStatus) string { return js.Branch })
+	case SortByActor:
+		return compareText(func(js JobStatus) string { return js.Actor })
+	case SortByRunNumber:
+		return func(a, b JobStatus) bool { return a.RunNumber < b.RunNumber }
+	case SortByAge:
+		return func(a, b JobStatus) bool {
+			// Jobs that have not started yet have no age and go last
+			if a.StartedAt == nil || b.StartedAt == nil {
+				return a.StartedAt != nil && b.StartedAt == nil
+			}
+			return a.StartedAt.After(*b.StartedAt)
+		}
+	default:
+		return nil
+	}
+}
@@ -8,24 +8,35 @@ import (
 
 // JobStatus represents the status of a GitHub Actions job
 type JobStatus struct {
-	ID           int64
-	Name         string
-	RunID        int64
-	RunNumber    int
-	Status       string
-	Conclusion   string
-	StartedAt    *time.Time
-	CompletedAt  *time.Time
-	Environment  string
-	WorkflowName string
-	Branch       string
-	Event        string
-	Actor        string
-	Repository   string
+	ID           int64      `json:"id" yaml:"id"`
+	Name         string     `json:"name" yaml:"name"`
+	RunID        int64      `json:"run_id" yaml:"run_id"`
+	RunNumber    int        `json:"run_number" yaml:"run_number"`
+	Status       string     `json:"status" yaml:"status"`
+	Conclusion   string     `json:"conclusion,omitempty" yaml:"conclusion,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+	Environment  string     `json:"environment,omitempty" yaml:"environment,omitempty"`
+	WorkflowName string     `json:"workflow_name" yaml:"workflow_name"`
+	Branch       string     `json:"branch" yaml:"branch"`
+	Event        string     `json:"event" yaml:"event"`
+	Actor        string     `json:"actor" yaml:"actor"`
+	Repository   string     `json:"repository" yaml:"repository"`
+	
+	// Deployment review details, populated for waiting runs only
+	ReviewChecked bool     `json:"review_checked,omitempty" yaml:"review_checked,omitempty"` // Whether pending deployments were fetched for this run
+	CanApprove    bool     `json:"can_approve,omitempty" yaml:"can_approve,omitempty"`       // Whether the current user can approve at least one pending environment
+	Reviewers     []string `json:"reviewers,omitempty" yaml:"reviewers,omitempty"`           // Required reviewers across all pending environments
 	
 	// UI highlighting for newly scanned jobs
-	IsNewlyScanned bool      `json:"-"` // Track if this job was just discovered
-	HighlightUntil *time.Time `json:"-"` // When to stop highlighting this job
+	IsNewlyScanned bool      `json:"-" yaml:"-"` // Track if this job was just discovered
+	HighlightUntil *time.Time `json:"-" yaml:"-"` // When to stop highlighting this job
+}
+
+// IsApprovable returns false only when the run is known to have no pending
+// environment the current user can approve
+func (js JobStatus) IsApprovable() bool {
+	return !js.ReviewChecked || js.CanApprove
 }
 
 // RepoScanResult represents the result of scanning a repository
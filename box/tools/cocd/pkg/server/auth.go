@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// memberCacheTTL is how long the membership of a token is trusted before it is checked again
+	memberCacheTTL = 5 * time.Minute
+	// memberCheckTimeout bounds the GitHub request that checks a token
+	memberCheckTimeout = 10 * time.Second
+)
+
+// memberCache remembers which GitHub tokens belong to active members of the
+// served organization, so that the read endpoints check a token with GitHub
+// once every memberCacheTTL rather than on every request
+type memberCache struct {
+	baseURL string
+	http    *http.Client
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]memberEntry
+}
+
+type memberEntry struct {
+	member  bool
+	expires time.Time
+}
+
+func newMemberCache(baseURL string) *memberCache {
+	if baseURL == "" {
+		baseURL = "https://api.github.com"
+	}
+	return &memberCache{
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+		http:    &http.Client{Timeout: memberCheckTimeout},
+		entries: make(map[[sha256.Size]byte]memberEntry),
+	}
+}
+
+// isMember reports whether the Authorization header value belongs to an active
+// member of org. Only tokens are cached, hashed, never the header itself.
+func (c *memberCache) isMember(ctx context.Context, authorization, org string) (bool, error) {
+	key := sha256.Sum256([]byte(strings.ToLower(org) + "\x00" + authorization))
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.member, nil
+	}
+
+	member, err := c.checkMembership(ctx, authorization, org)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = memberEntry{member: member, expires: now.Add(memberCacheTTL)}
+	c.mu.Unlock()
+	return member, nil
+}
+
+// checkMembership asks GitHub for the caller's membership in org. Tokens that
+// GitHub rejects or that cannot see the organization are not members.
+func (c *memberCache) checkMembership(ctx context.Context, authorization, org string) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"user/memberships/orgs/"+org, nil)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Authorization", authorization)
+	request.Header.Set("Accept", "application/vnd.github+json")
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		var membership struct {
+			State string `json:"state"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&membership); err != nil {
+			return false, fmt.Errorf("invalid membership response: %w", err)
+		}
+		return membership.State == "active", nil
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("GitHub answered %s", response.Status)
+	}
+}
+
+// requireMember serves a read endpoint only to callers whose GitHub token
+// belongs to a member of the served organization, since the jobs reveal the
+// repositories, branches and actors the server's token can see
+func (s *Server) requireMember(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "a GitHub token is required"})
+			return
+		}
+
+		org := s.monitor.GetOrganization()
+		member, err := s.members.isMember(r.Context(), authorization, org)
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, ErrorResponse{Error: fmt.Sprintf("failed to verify the GitHub token: %v", err)})
+			return
+		}
+		if !member {
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: fmt.Sprintf("the GitHub token is not a member of %s", org)})
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// GitHubProxyPrefix is the path under which the server forwards GitHub API
+// requests of remote clients, for use as a GitHub client base URL
+const GitHubProxyPrefix = "/api/v1/github/"
+
+// githubProxyRoutes are the GitHub API endpoints the TUI needs to show run
+// details and logs and to review or cancel runs. Anything else is not forwarded.
+var githubProxyRoutes = []string{
+	"GET /user",
+	"GET /repos/{owner}/{repo}/actions/runs/{run_id}",
+	"GET /repos/{owner}/{repo}/actions/runs/{run_id}/jobs",
+	"GET /repos/{owner}/{repo}/actions/runs/{run_id}/pending_deployments",
+	"POST /repos/{owner}/{repo}/actions/runs/{run_id}/pending_deployments",
+	"POST /repos/{owner}/{repo}/actions/runs/{run_id}/cancel",
+	"GET /repos/{owner}/{repo}/actions/jobs/{job_id}",
+	"GET /repos/{owner}/{repo}/actions/jobs/{job_id}/logs",
+}
+
+// newGitHubProxy forwards requests to the GitHub API at baseURL. The caller's
+// Authorization header is passed through as is, so every action is taken with
+// the caller's own token and never with the server's.
+func newGitHubProxy(baseURL string) (*httputil.ReverseProxy, error) {
+	if baseURL == "" {
+		baseURL = "https://api.github.com"
+	}
+	upstream, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub base URL: %w", err)
+	}
+
+	return &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			r.Out.URL.Path = upstream.Path + strings.TrimPrefix(r.In.URL.Path, GitHubProxyPrefix)
+			r.Out.URL.RawPath = ""
+		},
+	}, nil
+}
+
+// handleGitHubProxy forwards an allowed GitHub API request of the server's organization
+func (s *Server) handleGitHubProxy(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" {
+		writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "a GitHub token is required"})
+		return
+	}
+
+	owner := r.PathValue("owner")
+	if owner != "" && !strings.EqualFold(owner, s.monitor.GetOrganization()) {
+		writeJSON(w, http.StatusForbidden, ErrorResponse{Error: fmt.Sprintf("organization %s is not served here", owner)})
+		return
+	}
+
+	s.githubProxy.ServeHTTP(w, r)
+}
@@ -0,0 +1,335 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+const (
+	// eventBufferSize is how many events a slow SSE client may fall behind before it is disconnected
+	eventBufferSize = 64
+	// keepAliveInterval keeps idle SSE connections open through proxies
+	keepAliveInterval = 30 * time.Second
+)
+
+// InfoResponse is the body of the info endpoint, used by remote clients to set themselves up
+type InfoResponse struct {
+	Organization string `json:"organization"`
+	GitHubURL    string `json:"github_url"`
+	ScanInterval int    `json:"scan_interval_seconds"`
+}
+
+// JobsResponse is the body of the jobs endpoints
+type JobsResponse struct {
+	Organization string              `json:"organization"`
+	ScannedAt    time.Time           `json:"scanned_at"`
+	Jobs         []scanner.JobStatus `json:"jobs"`
+}
+
+// ProgressResponse is the body of the progress endpoint
+type ProgressResponse struct {
+	Progress  monitor.ScanProgress `json:"progress"`
+	ScannedAt *time.Time           `json:"scanned_at,omitempty"`
+	LastError string               `json:"last_error,omitempty"`
+	// WebhookError is why the last webhook delivery could not be applied
+	WebhookError string `json:"webhook_error,omitempty"`
+}
+
+// ErrorResponse is the body of failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server serves the jobs found by a single shared monitor over a read-only
+// HTTP/JSON API, so that many users can share one scanner. Reviews and
+// cancellations of remote clients are forwarded to GitHub with their own token.
+type Server struct {
+	monitor     *monitor.Monitor
+	baseURL     string // GitHub API URL, used to link events to the Actions page
+	githubProxy *httputil.ReverseProxy
+	members     *memberCache
+
+	mu        sync.RWMutex
+	jobs      []scanner.JobStatus
+	scannedAt *time.Time
+	lastError string
+	differ    *monitor.EventDiffer
+
+	webhooks     *webhookQueue
+	webhookOnce  sync.Once
+	webhookError string
+
+	subscribersMu sync.Mutex
+	subscribers   map[chan monitor.Event]struct{}
+
+	observersMu sync.Mutex
+	observers   []func(jobs []scanner.JobStatus, at time.Time)
+}
+
+// New creates a server for a monitor. Jobs are served once Update received the first scan.
+func New(mon *monitor.Monitor, baseURL string) (*Server, error) {
+	githubProxy, err := newGitHubProxy(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		monitor:     mon,
+		baseURL:     baseURL,
+		githubProxy: githubProxy,
+		members:     newMemberCache(baseURL),
+		webhooks:    newWebhookQueue(),
+		differ:      monitor.NewEventDiffer(monitor.DefaultEventRetention),
+		subscribers: make(map[chan monitor.Event]struct{}),
+	}, nil
+}
+
+// Update stores the jobs of a scan started by monitor.StartScanning and
+// broadcasts the run state changes to the event stream. Runs a webhook updated
+// while the scan was running are kept when they are newer than the scanned ones.
+func (s *Server) Update(result monitor.ScanResult) {
+	s.mu.Lock()
+	if result.Err != nil {
+		s.lastError = result.Err.Error()
+		s.mu.Unlock()
+		return
+	}
+
+	at := result.At
+	jobs := mergeScan(s.jobs, result.Jobs, at.Add(-result.Duration))
+	s.jobs = jobs
+	s.scannedAt = &at
+	s.lastError = ""
+	events := s.differ.Diff(jobs, at)
+	s.notify(jobs, at)
+	s.mu.Unlock()
+
+	for _, event := range events {
+		event.URL = s.actionsURL(event.Job)
+		s.broadcast(event)
+	}
+}
+
+// OnJobsChanged calls observe with all jobs after every scan and webhook
+// update, for consumers such as metrics that must follow both
+func (s *Server) OnJobsChanged(observe func(jobs []scanner.JobStatus, at time.Time)) {
+	s.observersMu.Lock()
+	defer s.observersMu.Unlock()
+	s.observers = append(s.observers, observe)
+}
+
+// notify is called with s.mu held, so that observers see the updates in order
+func (s *Server) notify(jobs []scanner.JobStatus, at time.Time) {
+	s.observersMu.Lock()
+	observers := s.observers
+	s.observersMu.Unlock()
+
+	for _, observe := range observers {
+		observe(jobs, at)
+	}
+}
+
+// mergeScan returns the scanned jobs, except where a known copy of a run is
+// newer, plus the runs only known from webhooks updated since the scan started
+func mergeScan(known, scanned []scanner.JobStatus, startedAt time.Time) []scanner.JobStatus {
+	byRunID := make(map[int64]scanner.JobStatus, len(known))
+	for _, job := range known {
+		byRunID[job.RunID] = job
+	}
+
+	merged := make([]scanner.JobStatus, 0, len(scanned))
+	inScan := make(map[int64]bool, len(scanned))
+	for _, job := range scanned {
+		inScan[job.RunID] = true
+		if existing, ok := byRunID[job.RunID]; ok && isNewer(existing, job) {
+			job = existing
+		}
+		merged = append(merged, job)
+	}
+
+	var webhookOnly []scanner.JobStatus
+	for _, job := range known {
+		if !inScan[job.RunID] && job.CompletedAt != nil && job.CompletedAt.After(startedAt) {
+			webhookOnly = append(webhookOnly, job)
+		}
+	}
+	return append(webhookOnly, merged...)
+}
+
+// isNewer reports whether a copy of a run was updated by GitHub after another,
+// judging by CompletedAt, which holds the run's updated_at
+func isNewer(job, other scanner.JobStatus) bool {
+	return job.CompletedAt != nil && other.CompletedAt != nil && job.CompletedAt.After(*other.CompletedAt)
+}
+
+// Handler returns the API routes. Every route needs the GitHub token of a
+// member of the organization, which the proxied routes pass on to GitHub.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/info", s.requireMember(s.handleInfo))
+	mux.HandleFunc("GET /api/v1/jobs/pending", s.requireMember(s.handlePendingJobs))
+	mux.HandleFunc("GET /api/v1/jobs/recent", s.requireMember(s.handleRecentJobs))
+	mux.HandleFunc("GET /api/v1/progress", s.requireMember(s.handleProgress))
+	mux.HandleFunc("GET /api/v1/events", s.requireMember(s.handleEvents))
+	for _, route := range githubProxyRoutes {
+		method, path, _ := strings.Cut(route, " ")
+		mux.HandleFunc(method+" "+strings.TrimSuffix(GitHubProxyPrefix, "/")+path, s.handleGitHubProxy)
+	}
+	return mux
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, InfoResponse{
+		Organization: s.monitor.GetOrganization(),
+		GitHubURL:    s.baseURL,
+		ScanInterval: s.monitor.GetUpdateInterval(),
+	})
+}
+
+func (s *Server) handlePendingJobs(w http.ResponseWriter, r *http.Request) {
+	s.serveJobs(w, r, func(job scanner.JobStatus) bool { return job.Status == "waiting" })
+}
+
+func (s *Server) handleRecentJobs(w http.ResponseWriter, r *http.Request) {
+	s.serveJobs(w, r, func(job scanner.JobStatus) bool { return true })
+}
+
+// serveJobs writes the jobs of the last scan that match keep and the optional filter query parameter
+func (s *Server) serveJobs(w http.ResponseWriter, r *http.Request, keep func(scanner.JobStatus) bool) {
+	filter, err := scanner.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid filter: %v", err)})
+		return
+	}
+
+	s.mu.RLock()
+	scannedAt := s.scannedAt
+	all := s.jobs
+	s.mu.RUnlock()
+
+	if scannedAt == nil {
+		writeJSON(w, http.StatusServiceUnavailable, ErrorResponse{Error: "the first scan has not completed yet"})
+		return
+	}
+
+	jobs := []scanner.JobStatus{}
+	for _, job := range all {
+		if keep(job) && filter.Match(job) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, JobsResponse{
+		Organization: s.monitor.GetOrganization(),
+		ScannedAt:    *scannedAt,
+		Jobs:         jobs,
+	})
+}
+
+func (s *Server) handleProgress(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	response := ProgressResponse{
+		Progress:     s.monitor.GetScanProgress(),
+		ScannedAt:    s.scannedAt,
+		LastError:    s.lastError,
+		WebhookError: s.webhookError,
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleEvents streams run state changes as server-sent events until the client disconnects
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "streaming is not supported"})
+		return
+	}
+
+	events := s.subscribe()
+	defer s.unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and refetches the jobs
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) subscribe() chan monitor.Event {
+	events := make(chan monitor.Event, eventBufferSize)
+
+	s.subscribersMu.Lock()
+	s.subscribers[events] = struct{}{}
+	s.subscribersMu.Unlock()
+
+	return events
+}
+
+func (s *Server) unsubscribe(events chan monitor.Event) {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+
+	if _, ok := s.subscribers[events]; ok {
+		delete(s.subscribers, events)
+		close(events)
+	}
+}
+
+// broadcast sends an event to every subscriber, disconnecting those whose buffer is full
+func (s *Server) broadcast(event monitor.Event) {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+
+	for events := range s.subscribers {
+		select {
+		case events <- event:
+		default:
+			delete(s.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+func (s *Server) actionsURL(job scanner.JobStatus) string {
+	return job.GetActionsURL(s.baseURL, s.monitor.GetOrganization())
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
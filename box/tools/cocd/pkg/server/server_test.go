@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ghclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/monitor"
+)
+
+// fakeGitHub serves an organization with one repository whose only waiting run
+// is older than its 10 latest runs
+func fakeGitHub(t *testing.T) *httptest.Server {
+	t.Helper()
+	now := time.Now().UTC()
+	run := func(id int, status string, age time.Duration) map[string]interface{} {
+		created := now.Add(-age).Format(time.RFC3339)
+		return map[string]interface{}{
+			"id": id, "run_number": id, "name": "deploy", "status": status, "head_branch": "main",
+			"created_at": created, "updated_at": created,
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"name": "app", "full_name": "acme/app", "pushed_at": now.Format(time.RFC3339), "owner": map[string]string{"login": "acme"}},
+		})
+	})
+	mux.HandleFunc("GET /repos/acme/app/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		var runs []map[string]interface{}
+		if r.URL.Query().Get("status") == "waiting" {
+			runs = append(runs, run(1, "waiting", 3*time.Hour))
+		} else {
+			for id := 20; id > 10; id-- {
+				runs = append(runs, run(id, "completed", time.Duration(20-id)*time.Minute))
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total_count": len(runs), "workflow_runs": runs})
+	})
+	mux.HandleFunc("GET /repos/acme/app/actions/runs/{id}/pending_deployments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"environment": {"id": 1, "name": "production"}, "current_user_can_approve": true}]`)
+	})
+	mux.HandleFunc("GET /user/memberships/orgs/acme", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"state": "active"}`)
+	})
+
+	gh := httptest.NewServer(mux)
+	t.Cleanup(gh.Close)
+	return gh
+}
+
+func TestPendingJobsIncludeOldWaitingRuns(t *testing.T) {
+	gh := fakeGitHub(t)
+	client, err := ghclient.NewClient("token", gh.URL, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mon := monitor.NewMonitorWithOptions(client, monitor.Options{Interval: 3600})
+	api, err := New(mon, gh.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	scanned := make(chan monitor.ScanResult, 1)
+	go mon.StartScanning(ctx, func(result monitor.ScanResult) {
+		api.Update(result)
+		select {
+		case scanned <- result:
+		default:
+		}
+	})
+
+	select {
+	case result := <-scanned:
+		if result.Err != nil {
+			t.Fatalf("scan failed: %v", result.Err)
+		}
+	case <-ctx.Done():
+		t.Fatal("no scan completed")
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/pending", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", recorder.Code, recorder.Body)
+	}
+
+	var response JobsResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Jobs) != 1 || response.Jobs[0].RunID != 1 {
+		t.Fatalf("pending jobs = %+v, want the waiting run 1", response.Jobs)
+	}
+	if response.Jobs[0].Environment != "production" {
+		t.Errorf("environment = %q, want %q", response.Jobs[0].Environment, "production")
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+const (
+	// maxWebhookPayload is the largest delivery GitHub sends
+	maxWebhookPayload = 25 << 20
+	// webhookTimeout bounds the GitHub requests made for one delivery
+	webhookTimeout = 30 * time.Second
+	// webhookWorkers is how many deliveries are applied at the same time
+	webhookWorkers = 4
+	// maxQueuedWebhooks is how many runs may wait to be updated before
+	// deliveries about other runs are refused
+	maxQueuedWebhooks = 256
+)
+
+// webhookEvents are the deliveries that change the state of a run
+var webhookEvents = map[string]bool{
+	"workflow_run":               true,
+	"workflow_job":               true,
+	"deployment_review":          true,
+	"deployment_protection_rule": true,
+}
+
+// callbackRunID finds the run in the deployment_callback_url of a deployment_protection_rule delivery
+var callbackRunID = regexp.MustCompile(`/actions/runs/(\d+)/`)
+
+// webhookDelivery holds the fields cocd reads from the supported deliveries
+type webhookDelivery struct {
+	Repo                  *github.Repository  `json:"repository"`
+	WorkflowRun           *github.WorkflowRun `json:"workflow_run"`
+	WorkflowJob           *github.WorkflowJob `json:"workflow_job"`
+	DeploymentCallbackURL string              `json:"deployment_callback_url"`
+}
+
+// runID returns the workflow run a delivery is about, zero if it names none
+func (d webhookDelivery) runID() int64 {
+	switch {
+	case d.WorkflowRun.GetID() != 0:
+		return d.WorkflowRun.GetID()
+	case d.WorkflowJob.GetRunID() != 0:
+		return d.WorkflowJob.GetRunID()
+	}
+	if match := callbackRunID.FindStringSubmatch(d.DeploymentCallbackURL); match != nil {
+		id, _ := strconv.ParseInt(match[1], 10, 64)
+		return id
+	}
+	return 0
+}
+
+// webhookTask is a delivery waiting in the queue of the webhook workers
+type webhookTask struct {
+	event    string
+	delivery webhookDelivery
+}
+
+// webhookQueue hands the deliveries to a fixed number of workers, so that a
+// burst of deliveries does not turn into as many concurrent GitHub requests.
+// Deliveries about a run that is already queued replace the queued one.
+type webhookQueue struct {
+	runIDs chan int64
+
+	mu      sync.Mutex
+	pending map[int64]webhookTask
+}
+
+func newWebhookQueue() *webhookQueue {
+	return &webhookQueue{
+		runIDs:  make(chan int64, maxQueuedWebhooks),
+		pending: make(map[int64]webhookTask),
+	}
+}
+
+// add queues a delivery and reports whether there was room for it
+func (q *webhookQueue) add(task webhookTask) bool {
+	runID := task.delivery.runID()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, queued := q.pending[runID]; queued {
+		q.pending[runID] = task
+		return true
+	}
+	if len(q.pending) >= maxQueuedWebhooks {
+		return false
+	}
+	q.pending[runID] = task
+	q.runIDs <- runID
+	return true
+}
+
+// next waits for the next delivery to apply
+func (q *webhookQueue) next() webhookTask {
+	runID := <-q.runIDs
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	task := q.pending[runID]
+	delete(q.pending, runID)
+	return task
+}
+
+// WebhookHandler receives GitHub webhook deliveries signed with secret and
+// updates the run they are about right away, instead of at the next scan.
+// Deliveries of other organizations and other events are acknowledged and ignored.
+func (s *Server) WebhookHandler(secret string) http.Handler {
+	s.webhookOnce.Do(func() {
+		for i := 0; i < webhookWorkers; i++ {
+			go func() {
+				for {
+					task := s.webhooks.next()
+					s.applyWebhook(task.event, task.delivery)
+				}
+			}()
+		}
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "webhook deliveries must be POST requests"})
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxWebhookPayload)
+		payload, err := github.ValidatePayload(r, []byte(secret))
+		if err != nil {
+			writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "invalid webhook signature"})
+			return
+		}
+
+		event := github.WebHookType(r)
+		if !webhookEvents[event] {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var delivery webhookDelivery
+		if err := json.Unmarshal(payload, &delivery); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid webhook payload"})
+			return
+		}
+		if !strings.EqualFold(delivery.Repo.GetOwner().GetLogin(), s.monitor.GetOrganization()) || delivery.runID() == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// GitHub expects an answer within ten seconds, fetching the run may take longer.
+		// A refused delivery can be redelivered, and the next scan reconciles the run anyway.
+		if !s.webhooks.add(webhookTask{event: event, delivery: delivery}) {
+			writeJSON(w, http.StatusServiceUnavailable, ErrorResponse{Error: "too many webhook deliveries are waiting"})
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// applyWebhook updates the run of a delivery. Only workflow_run deliveries carry
+// the whole run; for the others it is read from GitHub along with its pending
+// deployments. A failure to read it is reported by the progress endpoint.
+func (s *Server) applyWebhook(event string, delivery webhookDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	client := s.monitor.GetClient()
+	repo := delivery.Repo.GetName()
+	run := delivery.WorkflowRun
+	if event != "workflow_run" || run == nil {
+		var err error
+		run, _, err = client.GetWorkflowRun(ctx, repo, delivery.runID())
+		if err != nil {
+			s.setWebhookError(fmt.Sprintf("failed to read run %d of %s for a %s delivery: %v", delivery.runID(), repo, event, err))
+			return
+		}
+	}
+
+	s.UpdateJob(scanner.NewRecentJobsScanner(client).ScanRun(ctx, repo, run), time.Now())
+	s.setWebhookError("")
+}
+
+// setWebhookError records the failure of the last delivery, empty when it was applied
+func (s *Server) setWebhookError(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhookError = message
+}
+
+// UpdateJob replaces a run in the jobs of the last scan, or adds it as the
+// newest one, and broadcasts its state change. A run older than the one already
+// known, from deliveries that arrived out of order, is ignored. The next scan
+// reconciles the jobs with GitHub in case a delivery was missed.
+func (s *Server) UpdateJob(job scanner.JobStatus, at time.Time) {
+	s.mu.Lock()
+	jobs := make([]scanner.JobStatus, 0, len(s.jobs)+1)
+	found := false
+	for _, existing := range s.jobs {
+		if existing.RunID == job.RunID {
+			found = true
+			if isNewer(existing, job) {
+				s.mu.Unlock()
+				return
+			}
+			existing = job
+		}
+		jobs = append(jobs, existing)
+	}
+	if !found {
+		jobs = append([]scanner.JobStatus{job}, jobs...)
+	}
+	s.jobs = jobs
+	events := s.differ.Diff(jobs, at)
+	s.notify(jobs, at)
+	s.mu.Unlock()
+
+	for _, event := range events {
+		event.URL = s.actionsURL(event.Job)
+		s.broadcast(event)
+	}
+}
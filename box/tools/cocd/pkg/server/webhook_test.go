@@ -0,0 +1,229 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	ghclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+const testWebhookSecret = "s3cret"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	gh := fakeGitHub(t)
+	client, err := ghclient.NewClient("token", gh.URL, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := New(monitor.NewMonitorWithOptions(client, monitor.Options{Interval: 3600}), gh.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func workflowRunPayload(owner string, runID int64, status string) string {
+	return fmt.Sprintf(`{
+		"repository": {"name": "app", "owner": {"login": %q}},
+		"workflow_run": {"id": %d, "run_number": %d, "name": "deploy", "status": %q, "conclusion": "success",
+			"created_at": "2026-10-18T10:00:00Z", "updated_at": "2026-10-18T10:05:00Z"}
+	}`, owner, runID, runID, status)
+}
+
+func deliver(handler http.Handler, event, payload, signature string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-GitHub-Event", event)
+	if signature != "" {
+		request.Header.Set("X-Hub-Signature-256", signature)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (s *Server) job(runID int64) (scanner.JobStatus, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, job := range s.jobs {
+		if job.RunID == runID {
+			return job, true
+		}
+	}
+	return scanner.JobStatus{}, false
+}
+
+func TestWebhookSignature(t *testing.T) {
+	handler := newTestServer(t).WebhookHandler(testWebhookSecret)
+	payload := workflowRunPayload("acme", 1, "completed")
+
+	tests := []struct {
+		name      string
+		signature string
+		want      int
+	}{
+		{"missing signature", "", http.StatusUnauthorized},
+		{"wrong secret", "sha256=" + strings.Repeat("0", 64), http.StatusUnauthorized},
+		{"signature of another payload", sign(payload + " "), http.StatusUnauthorized},
+		{"valid signature", sign(payload), http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deliver(handler, "workflow_run", payload, tt.signature).Code; got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookAppliesDeliveriesOfTheOrganization(t *testing.T) {
+	api := newTestServer(t)
+	handler := api.WebhookHandler(testWebhookSecret)
+
+	other := workflowRunPayload("other-org", 2, "completed")
+	if got := deliver(handler, "workflow_run", other, sign(other)).Code; got != http.StatusNoContent {
+		t.Errorf("delivery of another organization: status = %d, want %d", got, http.StatusNoContent)
+	}
+	ping := `{"zen": "Keep it logically awesome."}`
+	if got := deliver(handler, "ping", ping, sign(ping)).Code; got != http.StatusNoContent {
+		t.Errorf("unsupported event: status = %d, want %d", got, http.StatusNoContent)
+	}
+
+	ours := workflowRunPayload("ACME", 1, "completed")
+	if got := deliver(handler, "workflow_run", ours, sign(ours)).Code; got != http.StatusAccepted {
+		t.Fatalf("delivery of the organization: status = %d, want %d", got, http.StatusAccepted)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := api.job(1); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("run 1 of the delivery was not applied")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok := api.job(2); ok {
+		t.Error("run 2 of another organization was applied")
+	}
+}
+
+func TestUpdateJobIgnoresOlderDeliveries(t *testing.T) {
+	api := newTestServer(t)
+	older, newer := time.Now().Add(-time.Minute), time.Now()
+
+	api.UpdateJob(scanner.JobStatus{RunID: 1, Status: "completed", CompletedAt: &newer}, newer)
+	api.UpdateJob(scanner.JobStatus{RunID: 1, Status: "waiting", CompletedAt: &older}, newer)
+
+	job, ok := api.job(1)
+	if !ok || job.Status != "completed" {
+		t.Errorf("run 1 = %+v, want the newer completed copy", job)
+	}
+}
+
+func TestMergeScan(t *testing.T) {
+	scanStart := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		updated := scanStart.Add(time.Duration(minutes) * time.Minute)
+		return &updated
+	}
+
+	known := []scanner.JobStatus{
+		{RunID: 1, Status: "completed", CompletedAt: at(2)},   // A webhook is newer than the scan
+		{RunID: 2, Status: "waiting", CompletedAt: at(-5)},    // The scan is newer than the webhook
+		{RunID: 3, Status: "queued", CompletedAt: at(1)},      // Only a webhook since the scan started
+		{RunID: 4, Status: "completed", CompletedAt: at(-60)}, // Left the scans before it started
+	}
+	scanned := []scanner.JobStatus{
+		{RunID: 1, Status: "waiting", CompletedAt: at(1)},
+		{RunID: 2, Status: "in_progress", CompletedAt: at(1)},
+		{RunID: 5, Status: "queued", CompletedAt: at(1)},
+	}
+
+	merged := mergeScan(known, scanned, scanStart)
+
+	want := []struct {
+		runID  int64
+		status string
+	}{{3, "queued"}, {1, "completed"}, {2, "in_progress"}, {5, "queued"}}
+	if len(merged) != len(want) {
+		t.Fatalf("merged = %+v, want %d runs", merged, len(want))
+	}
+	for i, w := range want {
+		if merged[i].RunID != w.runID || merged[i].Status != w.status {
+			t.Errorf("merged[%d] = run %d %s, want run %d %s", i, merged[i].RunID, merged[i].Status, w.runID, w.status)
+		}
+	}
+}
+
+func TestWebhookQueueCoalescesRuns(t *testing.T) {
+	queue := newWebhookQueue()
+	task := func(runID int64, status string) webhookTask {
+		return webhookTask{event: "workflow_run", delivery: webhookDelivery{WorkflowRun: &github.WorkflowRun{ID: github.Int64(runID), Status: github.String(status)}}}
+	}
+
+	queue.add(task(1, "queued"))
+	queue.add(task(1, "in_progress"))
+	for runID := int64(2); runID <= maxQueuedWebhooks; runID++ {
+		if !queue.add(task(runID, "queued")) {
+			t.Fatalf("run %d refused before the queue is full", runID)
+		}
+	}
+	if queue.add(task(maxQueuedWebhooks+1, "queued")) {
+		t.Error("a new run was queued past maxQueuedWebhooks")
+	}
+	if !queue.add(task(1, "completed")) {
+		t.Error("a delivery of a queued run was refused")
+	}
+
+	first := queue.next()
+	if first.delivery.runID() != 1 || first.delivery.WorkflowRun.GetStatus() != "completed" {
+		t.Errorf("first task = run %d %s, want the last delivery of run 1", first.delivery.runID(), first.delivery.WorkflowRun.GetStatus())
+	}
+	if len(queue.runIDs) != maxQueuedWebhooks-1 {
+		t.Errorf("queued runs = %d, want %d", len(queue.runIDs), maxQueuedWebhooks-1)
+	}
+}
+
+func TestWebhookReportsRunsItCannotRead(t *testing.T) {
+	api := newTestServer(t)
+	handler := api.WebhookHandler(testWebhookSecret)
+
+	payload := `{"repository": {"name": "app", "owner": {"login": "acme"}}, "workflow_job": {"id": 7, "run_id": 99}}`
+	if got := deliver(handler, "workflow_job", payload, sign(payload)).Code; got != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", got, http.StatusAccepted)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		api.mu.RLock()
+		message := api.webhookError
+		api.mu.RUnlock()
+		if strings.Contains(message, "run 99") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("webhook error = %q, want the failure to read run 99", message)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -10,6 +11,12 @@ import (
 	"github.com/younsl/cocd/pkg/scanner"
 )
 
+// detailRefreshInterval is how often the job detail pane reloads while the run is in progress
+const detailRefreshInterval = 5 * time.Second
+
+// logViewerChrome is the number of lines the log viewer uses around the log itself
+const logViewerChrome = 6
+
 // BubbleApp is the main Bubble Tea application model
 type BubbleApp struct {
 	monitor Monitor
@@ -36,6 +43,9 @@ type BubbleApp struct {
 	showWaitingOnly bool
 	scanning     bool
 	
+	// Per-run outcome of the last bulk action, shown in the status area
+	bulkResults []BulkResult
+	
 	jobsChan chan []scanner.JobStatus
 	updateChan chan tea.Msg
 }
@@ -73,6 +83,7 @@ func NewBubbleApp(m Monitor, config *AppConfig) *BubbleApp {
 // Init initializes the Bubble Tea application
 func (app *BubbleApp) Init() tea.Cmd {
 	return tea.Batch(
+		app.commandHandler.LoadHistory(),
 		app.commandHandler.StartMonitoring(app.ctx, app.jobsChan),
 		app.commandHandler.LoadRecentJobsStreaming(app.ctx, app.updateChan),
 		app.commandHandler.TickCmd(),
@@ -99,19 +110,25 @@ func (app *BubbleApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		app.width = msg.Width
 		app.height = msg.Height
+		if viewer := app.viewManager.GetLogViewer(); viewer != nil {
+			viewer.SetSize(app.logViewerSize())
+		}
 		return app, nil
 		
 	case tea.KeyMsg:
 		return app.keyHandler.HandleKeyPress(msg, app)
 		
 	case jobsMsg:
-		return app.handleJobsMessage(msg)
+		return app.keepSelection(func() (tea.Model, tea.Cmd) { return app.handleJobsMessage(msg) })
 		
 	case pendingJobsMsg:
-		return app.handlePendingJobsMessage(msg)
+		return app.keepSelection(func() (tea.Model, tea.Cmd) { return app.handlePendingJobsMessage(msg) })
 		
 	case recentJobsMsg:
-		return app.handleRecentJobsMessage(msg)
+		return app.keepSelection(func() (tea.Model, tea.Cmd) { return app.handleRecentJobsMessage(msg) })
+		
+	case historyMsg:
+		return app.keepSelection(func() (tea.Model, tea.Cmd) { return app.handleHistoryMessage(msg) })
 		
 	case errorMsg:
 		return app.handleErrorMessage(msg)
@@ -120,10 +137,10 @@ func (app *BubbleApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return app.handleTickMessage(msg)
 		
 	case jobUpdateMsg:
-		return app.handleJobUpdateMessage(msg)
+		return app.keepSelection(func() (tea.Model, tea.Cmd) { return app.handleJobUpdateMessage(msg) })
 		
 	case recentJobUpdateMsg:
-		return app.handleRecentJobUpdateMessage(msg)
+		return app.keepSelection(func() (tea.Model, tea.Cmd) { return app.handleRecentJobUpdateMessage(msg) })
 	
 	case scanProgressMsg:
 		return app, nil
@@ -147,6 +164,41 @@ func (app *BubbleApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Refresh the current view to see updated status
 		return app.refreshCurrentView()
 		
+	case workflowJobsMsg:
+		if job := app.viewManager.GetDetailTargetJob(); job != nil && job.RunID == msg.runID {
+			app.viewManager.SetDetailJobs(msg.jobs)
+			app.errorMsg = ""
+		}
+		return app, nil
+		
+	case jobLogsMsg:
+		if viewer := app.viewManager.GetLogViewer(); viewer != nil && app.viewManager.GetLogJobID() == msg.jobID {
+			viewer.SetContent(msg.content)
+		}
+		return app, nil
+		
+	case pendingDeploymentsMsg:
+		if job := app.viewManager.GetApprovalTargetJob(); job != nil && job.RunID == msg.runID {
+			app.viewManager.SetApprovalEnvironments(msg.deployments)
+		}
+		return app, nil
+		
+	case bulkResultMsg:
+		app.viewManager.HideBulkConfirm()
+		app.viewManager.ClearSelection()
+		app.bulkResults = msg.results
+		app.errorMsg = ""
+		// Give GitHub a moment to apply the changes before refreshing
+		return app, app.commandHandler.DelayedRefresh(3 * time.Second)
+		
+	case organizationSwitchedMsg:
+		return app.handleOrganizationSwitched(msg)
+		
+	case rejectionSuccessMsg:
+		app.viewManager.HideRejectionConfirm()
+		// Refresh the current view to see updated status
+		return app.refreshCurrentView()
+		
 	case approvalProcessingMsg:
 		app.viewManager.HideApprovalConfirm()
 		// Silently wait and then refresh to sync with GitHub
@@ -167,6 +219,24 @@ func (app *BubbleApp) View() string {
 		return app.uiRenderer.RenderHelp(app.monitor)
 	}
 	
+	if viewer := app.viewManager.GetLogViewer(); viewer != nil {
+		return app.uiRenderer.RenderLogViewer(viewer)
+	}
+	
+	if app.viewManager.IsShowingJobDetail() {
+		if job := app.viewManager.GetDetailTargetJob(); job != nil {
+			return app.uiRenderer.RenderJobDetail(JobDetail{
+				Job:          *job,
+				WorkflowJobs: app.viewManager.GetDetailJobs(),
+				Cursor:       app.viewManager.GetDetailCursor(),
+				Loading:      app.viewManager.IsDetailLoading(),
+				LoadedAt:     app.viewManager.GetDetailLoadedAt(),
+				AutoRefresh:  app.isDetailRunActive(),
+				Error:        app.errorMsg,
+			})
+		}
+	}
+	
 	if app.viewManager.IsShowingCancelConfirm() {
 		if job := app.viewManager.GetCancelTargetJob(); job != nil {
 			selection := app.viewManager.GetCancelSelection()
@@ -177,13 +247,69 @@ func (app *BubbleApp) View() string {
 	if app.viewManager.IsShowingApprovalConfirm() {
 		if job := app.viewManager.GetApprovalTargetJob(); job != nil {
 			selection := app.viewManager.GetApprovalSelection()
-			return app.uiRenderer.RenderApprovalConfirm(*job, selection)
+			popup := app.reviewPopup("approved", *job, selection)
+			popup.Environments = app.environmentOptions()
+			popup.EnvironmentCursor = app.viewManager.GetApprovalEnvironmentCursor()
+			return app.uiRenderer.RenderApprovalConfirm(popup)
+		}
+	}
+	
+	if app.viewManager.IsShowingBulkConfirm() {
+		jobs := app.viewManager.GetBulkTargetJobs()
+		popup := BulkPopup{
+			Action:    app.viewManager.GetBulkAction(),
+			Jobs:      jobs,
+			Selection: app.viewManager.GetBulkSelection(),
+			Editing:   app.viewManager.IsEditingComment(),
+			Comment:   app.viewManager.GetCommentInputView(),
+		}
+		if len(jobs) > 0 {
+			popup.Message = app.commandHandler.ReviewMessage("approved", jobs[0], app.viewManager.GetComment())
+		}
+		return app.uiRenderer.RenderBulkConfirm(popup)
+	}
+	
+	if app.viewManager.IsShowingRejectionConfirm() {
+		if job := app.viewManager.GetRejectionTargetJob(); job != nil {
+			selection := app.viewManager.GetRejectionSelection()
+			return app.uiRenderer.RenderRejectionConfirm(app.reviewPopup("rejected", *job, selection))
 		}
 	}
 	
 	return app.renderMain()
 }
 
+// reviewPopup collects the state shown in the approval and rejection popups
+func (app *BubbleApp) reviewPopup(action string, job scanner.JobStatus, selection int) ReviewPopup {
+	return ReviewPopup{
+		Job:       job,
+		Selection: selection,
+		Editing:   app.viewManager.IsEditingComment(),
+		Comment:   app.viewManager.GetCommentInputView(),
+		Message:   app.commandHandler.ReviewMessage(action, job, app.viewManager.GetComment()),
+	}
+}
+
+// environmentOptions converts the approval checklist into render state, nil while loading
+func (app *BubbleApp) environmentOptions() []EnvironmentOption {
+	deployments := app.viewManager.GetApprovalEnvironments()
+	if deployments == nil {
+		return nil
+	}
+	
+	options := make([]EnvironmentOption, 0, len(deployments))
+	for i, pd := range deployments {
+		options = append(options, EnvironmentOption{
+			Name:       pd.EnvironmentName(),
+			WaitTimer:  pd.WaitTimer,
+			CanApprove: pd.CurrentUserCanApprove,
+			Reviewers:  pd.ReviewerNames(),
+			Selected:   app.viewManager.IsApprovalEnvironmentSelected(i),
+		})
+	}
+	return options
+}
+
 // Message handlers
 
 func (app *BubbleApp) handleJobsMessage(msg jobsMsg) (tea.Model, tea.Cmd) {
@@ -215,6 +341,18 @@ func (app *BubbleApp) handleRecentJobsMessage(msg recentJobsMsg) (tea.Model, tea
 	return app, nil
 }
 
+func (app *BubbleApp) handleOrganizationSwitched(msg organizationSwitchedMsg) (tea.Model, tea.Cmd) {
+	app.config.Org = string(msg)
+	app.config.Repo = ""
+	app.jobs = nil
+	app.recentJobs = nil
+	app.errorMsg = ""
+	app.viewManager.SetRepositoryScope("")
+	
+	model, cmd := app.refreshCurrentView()
+	return model, tea.Batch(cmd, app.commandHandler.LoadHistory())
+}
+
 func (app *BubbleApp) handleErrorMessage(msg errorMsg) (tea.Model, tea.Cmd) {
 	app.errorMsg = string(msg)
 	app.loading = false
@@ -225,6 +363,17 @@ func (app *BubbleApp) handleErrorMessage(msg errorMsg) (tea.Model, tea.Cmd) {
 	if app.viewManager.IsShowingApprovalConfirm() {
 		app.viewManager.HideApprovalConfirm()
 	}
+	if app.viewManager.IsShowingRejectionConfirm() {
+		app.viewManager.HideRejectionConfirm()
+	}
+	if app.viewManager.IsShowingBulkConfirm() {
+		app.viewManager.HideBulkConfirm()
+	}
+	app.viewManager.SetDetailLoading(false)
+	if viewer := app.viewManager.GetLogViewer(); viewer != nil && viewer.IsLoading() {
+		viewer.SetError(app.errorMsg)
+		app.errorMsg = ""
+	}
 	
 	return app, nil
 }
@@ -232,6 +381,17 @@ func (app *BubbleApp) handleErrorMessage(msg errorMsg) (tea.Model, tea.Cmd) {
 func (app *BubbleApp) handleTickMessage(msg tickMsg) (tea.Model, tea.Cmd) {
 	app.monitor.GetProgressTracker().UpdateScanCountdown()
 	
+	// Keep the job detail pane fresh while the run is still going. Failed requests
+	// count too, so an error is retried at the refresh interval, not on every tick.
+	var detailCmd tea.Cmd
+	if app.viewManager.IsShowingJobDetail() && !app.viewManager.IsDetailLoading() &&
+		app.isDetailRunActive() && time.Since(app.viewManager.GetDetailRequestedAt()) > detailRefreshInterval {
+		if job := app.viewManager.GetDetailTargetJob(); job != nil {
+			app.viewManager.SetDetailLoading(true)
+			detailCmd = app.commandHandler.LoadWorkflowJobs(app.ctx, *job)
+		}
+	}
+	
 	progress := app.monitor.GetScanProgress()
 	isScanning := progress.ScanMode != "Idle" && progress.ScanMode != "Completed"
 	
@@ -242,16 +402,17 @@ func (app *BubbleApp) handleTickMessage(msg tickMsg) (tea.Model, tea.Cmd) {
 		return app, tea.Batch(
 			app.commandHandler.TickCmd(),
 			app.commandHandler.LoadRecentJobsStreaming(app.ctx, app.updateChan),
+			detailCmd,
 		)
 	}
 	
 	currentCountdown := app.monitor.GetScanProgress().ScanCountdown
 	if currentCountdown != app.lastCountdown {
 		app.lastCountdown = currentCountdown
-		return app, tea.Batch(app.commandHandler.TickCmd(), func() tea.Msg { return updateUIMsg{} })
+		return app, tea.Batch(app.commandHandler.TickCmd(), func() tea.Msg { return updateUIMsg{} }, detailCmd)
 	}
 	
-	return app, app.commandHandler.TickCmd()
+	return app, tea.Batch(app.commandHandler.TickCmd(), detailCmd)
 }
 
 
@@ -307,7 +468,7 @@ func (app *BubbleApp) showCancelConfirmation() (tea.Model, tea.Cmd) {
 	
 	selectedJob := jobs[cursor]
 	
-	if selectedJob.Status != "waiting" && selectedJob.Status != "queued" && selectedJob.Status != "in_progress" {
+	if !isCancellable(selectedJob) {
 		return app, nil
 	}
 	
@@ -332,10 +493,171 @@ func (app *BubbleApp) showApprovalConfirmation() (tea.Model, tea.Cmd) {
 		return app, nil
 	}
 	
+	if !selectedJob.IsApprovable() {
+		app.errorMsg = fmt.Sprintf("You are not a required reviewer for %s #%d (reviewers: %s)",
+			selectedJob.Repository, selectedJob.RunNumber, strings.Join(selectedJob.Reviewers, ", "))
+		return app, nil
+	}
+	
 	app.viewManager.ShowApprovalConfirm(selectedJob)
+	return app, app.commandHandler.LoadPendingDeployments(app.ctx, selectedJob)
+}
+
+// showBulkConfirmation opens the bulk confirmation popup for the selected runs
+func (app *BubbleApp) showBulkConfirmation(action string) (tea.Model, tea.Cmd) {
+	jobs := app.viewManager.GetSelectedJobs()
+	
+	if action == BulkApprove {
+		var approvable []scanner.JobStatus
+		for _, job := range jobs {
+			if job.Status == "waiting" && job.IsApprovable() {
+				approvable = append(approvable, job)
+			}
+		}
+		if len(approvable) == 0 {
+			app.errorMsg = "None of the selected runs are waiting on an environment you can approve"
+			return app, nil
+		}
+		jobs = approvable
+	}
+	
+	if action == BulkCancel {
+		var cancellable []scanner.JobStatus
+		for _, job := range jobs {
+			if isCancellable(job) {
+				cancellable = append(cancellable, job)
+			}
+		}
+		if len(cancellable) == 0 {
+			app.errorMsg = "None of the selected runs are queued, in progress or waiting"
+			return app, nil
+		}
+		jobs = cancellable
+	}
+	
+	app.bulkResults = nil
+	app.viewManager.ShowBulkConfirm(action, jobs)
 	return app, nil
 }
 
+// isCancellable reports whether a run has not finished yet, so that it can still be cancelled
+func isCancellable(job scanner.JobStatus) bool {
+	return job.Status == "waiting" || job.Status == "queued" || job.Status == "in_progress"
+}
+
+// toggleSelection marks or unmarks the run under the cursor and moves to the next row
+func (app *BubbleApp) toggleSelection() (tea.Model, tea.Cmd) {
+	jobs := app.getJobsForCurrentView()
+	cursor := app.viewManager.GetCursor()
+	if cursor >= len(jobs) {
+		return app, nil
+	}
+	
+	app.viewManager.ToggleJobSelection(jobs[cursor])
+	return app.moveCursorDown()
+}
+
+func (app *BubbleApp) showRejectionConfirmation() (tea.Model, tea.Cmd) {
+	jobs := app.getJobsForCurrentView()
+	if len(jobs) == 0 {
+		return app, nil
+	}
+	
+	cursor := app.viewManager.GetCursor()
+	if cursor >= len(jobs) {
+		return app, nil
+	}
+	
+	selectedJob := jobs[cursor]
+	
+	if selectedJob.Status != "waiting" {
+		return app, nil
+	}
+	
+	app.viewManager.ShowRejectionConfirm(selectedJob)
+	return app, nil
+}
+
+func (app *BubbleApp) showJobDetail() (tea.Model, tea.Cmd) {
+	jobs := app.getJobsForCurrentView()
+	if len(jobs) == 0 {
+		return app, nil
+	}
+	
+	cursor := app.viewManager.GetCursor()
+	if cursor >= len(jobs) {
+		return app, nil
+	}
+	
+	selectedJob := jobs[cursor]
+	app.viewManager.ShowJobDetail(selectedJob)
+	return app, app.commandHandler.LoadWorkflowJobs(app.ctx, selectedJob)
+}
+
+func (app *BubbleApp) refreshJobDetail() (tea.Model, tea.Cmd) {
+	job := app.viewManager.GetDetailTargetJob()
+	if job == nil || app.viewManager.IsDetailLoading() {
+		return app, nil
+	}
+	
+	app.viewManager.SetDetailLoading(true)
+	return app, app.commandHandler.LoadWorkflowJobs(app.ctx, *job)
+}
+
+func (app *BubbleApp) showJobLogs() (tea.Model, tea.Cmd) {
+	run := app.viewManager.GetDetailTargetJob()
+	jobs := app.viewManager.GetDetailJobs()
+	cursor := app.viewManager.GetDetailCursor()
+	if run == nil || cursor >= len(jobs) {
+		return app, nil
+	}
+	
+	workflowJob := jobs[cursor]
+	title := fmt.Sprintf("%s #%d  %s", run.Repository, run.RunNumber, workflowJob.GetName())
+	width, height := app.logViewerSize()
+	app.viewManager.OpenLogViewer(title, workflowJob.GetID(), width, height)
+	return app, app.commandHandler.LoadJobLogs(app.ctx, *run, workflowJob.GetID())
+}
+
+func (app *BubbleApp) reloadJobLogs() (tea.Model, tea.Cmd) {
+	run := app.viewManager.GetDetailTargetJob()
+	viewer := app.viewManager.GetLogViewer()
+	if run == nil || viewer == nil || viewer.IsLoading() {
+		return app, nil
+	}
+	
+	width, height := app.logViewerSize()
+	app.viewManager.OpenLogViewer(viewer.Title, app.viewManager.GetLogJobID(), width, height)
+	return app, app.commandHandler.LoadJobLogs(app.ctx, *run, app.viewManager.GetLogJobID())
+}
+
+// logViewerSize returns the viewport size of the log viewer for the current terminal
+func (app *BubbleApp) logViewerSize() (int, int) {
+	width, height := app.width, app.height-logViewerChrome
+	if width <= 0 {
+		width = 80
+	}
+	if height <= 0 {
+		height = 20
+	}
+	return width, height
+}
+
+// isDetailRunActive reports whether the run in the detail pane has unfinished jobs
+func (app *BubbleApp) isDetailRunActive() bool {
+	jobs := app.viewManager.GetDetailJobs()
+	if len(jobs) == 0 {
+		job := app.viewManager.GetDetailTargetJob()
+		return job != nil && (job.Status == "waiting" || job.Status == "queued" || job.Status == "in_progress")
+	}
+	
+	for _, job := range jobs {
+		if job.GetStatus() != "completed" {
+			return true
+		}
+	}
+	return false
+}
 
 func (app *BubbleApp) moveCursorUp() (tea.Model, tea.Cmd) {
 	app.viewManager.MoveCursor(-1, app.getMaxCursorPosition())
@@ -349,14 +671,14 @@ func (app *BubbleApp) moveCursorDown() (tea.Model, tea.Cmd) {
 
 func (app *BubbleApp) navigatePageLeft() (tea.Model, tea.Cmd) {
 	if app.viewManager.GetCurrentView() == ViewRecent {
-		app.viewManager.ChangePage(-1, len(app.recentJobs))
+		app.viewManager.ChangePage(-1, len(app.jobService.FilterJobs(ViewRecent, app.recentJobs, app.viewManager)))
 	}
 	return app, nil
 }
 
 func (app *BubbleApp) navigatePageRight() (tea.Model, tea.Cmd) {
 	if app.viewManager.GetCurrentView() == ViewRecent {
-		app.viewManager.ChangePage(1, len(app.recentJobs))
+		app.viewManager.ChangePage(1, len(app.jobService.FilterJobs(ViewRecent, app.recentJobs, app.viewManager)))
 	}
 	return app, nil
 }
@@ -368,25 +690,35 @@ func (app *BubbleApp) renderMain() string {
 	content.WriteString(app.uiRenderer.RenderHeader(app.monitor))
 	content.WriteString("\n")
 	
+	filteredRecentJobs := app.jobService.FilterJobs(ViewRecent, app.recentJobs, app.viewManager)
 	content.WriteString(app.uiRenderer.RenderViewSelector(
 		app.viewManager.GetCurrentView(),
-		len(app.jobs),
-		len(app.recentJobs),
+		len(app.jobService.FilterJobs(ViewPending, app.jobs, app.viewManager)),
+		len(filteredRecentJobs),
 		app.viewManager,
 	))
 	content.WriteString("\n")
 	
+	if commandBar := app.uiRenderer.RenderCommandBar(app.viewManager); commandBar != "" {
+		content.WriteString(commandBar)
+		content.WriteString("\n")
+	} else if filterBar := app.uiRenderer.RenderFilterBar(app.viewManager); filterBar != "" {
+		content.WriteString(filterBar)
+		content.WriteString("\n")
+	}
+	
 	jobs := app.getJobsForCurrentView()
 	content.WriteString(app.uiRenderer.RenderJobTable(jobs, app.viewManager.GetCursor(), app.viewManager))
 	content.WriteString("\n")
 	
 	if app.viewManager.GetCurrentView() == ViewRecent {
-		pagination := app.uiRenderer.RenderPagination(app.viewManager.GetCurrentView(), app.viewManager, len(app.recentJobs), jobs)
+		pagination := app.uiRenderer.RenderPagination(app.viewManager.GetCurrentView(), app.viewManager, len(filteredRecentJobs), jobs)
 		if pagination != "" {
 			content.WriteString(pagination)
 		}
 	}
 	
+	content.WriteString(app.uiRenderer.RenderBulkResults(app.bulkResults))
 	content.WriteString(app.uiRenderer.RenderStatus(app.errorMsg))
 	
 	return content.String()
@@ -512,8 +844,51 @@ func (app *BubbleApp) getJobsForCurrentView() []scanner.JobStatus {
 	)
 }
 
+// keepSelection runs an update that may reorder the job list and moves the cursor
+// back onto the run that was selected before it
+func (app *BubbleApp) keepSelection(update func() (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	key, ok := app.selectedJobKey()
+	model, cmd := update()
+	if ok {
+		app.focusJobKey(key)
+	}
+	return model, cmd
+}
+
+// selectedJobKey returns the key of the job under the cursor
+func (app *BubbleApp) selectedJobKey() (string, bool) {
+	view := app.viewManager.GetCurrentView()
+	jobs := app.jobService.ListJobsForView(view, app.jobs, app.recentJobs, app.viewManager)
+	
+	index := app.viewManager.GetCursor()
+	if view == ViewRecent {
+		page, perPage := app.viewManager.GetPageInfo()
+		index += page * perPage
+	}
+	if index >= len(jobs) {
+		return "", false
+	}
+	return jobKey(jobs[index]), true
+}
+
+// focusJobKey moves the cursor to the job with the given key, if it is still listed
+func (app *BubbleApp) focusJobKey(key string) {
+	view := app.viewManager.GetCurrentView()
+	for i, job := range app.jobService.ListJobsForView(view, app.jobs, app.recentJobs, app.viewManager) {
+		if jobKey(job) == key {
+			app.viewManager.FocusJob(i)
+			return
+		}
+	}
+}
+
+// jobKey identifies a job across scans
+func jobKey(job scanner.JobStatus) string {
+	return fmt.Sprintf("%s:%d:%d", job.Repository, job.RunID, job.ID)
+}
+
 func (app *BubbleApp) getMaxCursorPosition() int {
-	return app.viewManager.GetMaxCursorPosition(app.jobs, app.recentJobs)
+	return len(app.getJobsForCurrentView())
 }
 
 // RunBubbleApp runs the Bubble Tea application
@@ -0,0 +1,231 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// commandSpec describes a command accepted by the : prompt
+type commandSpec struct {
+	Name        string
+	Aliases     []string
+	Args        string
+	Description string
+}
+
+// commandSpecs lists the commands of the : prompt in the order they are shown in help
+var commandSpecs = []commandSpec{
+	{Name: "pending", Aliases: []string{"waiting"}, Description: "Show Approval Waiting Jobs"},
+	{Name: "recent", Description: "Show Recent Jobs"},
+	{Name: "repo", Args: "[name]", Description: "Show only one repository, or all when no name is given"},
+	{Name: "org", Args: "<name>", Description: "Monitor another organization"},
+	{Name: "filter", Args: "[query]", Description: "Filter jobs like /, or clear the filter when no query is given"},
+	{Name: "approve", Description: "Approve the selected deployment"},
+	{Name: "reject", Description: "Reject the selected deployment"},
+	{Name: "cancel", Description: "Cancel the selected workflow"},
+	{Name: "refresh", Description: "Refresh the current view"},
+	{Name: "help", Description: "Toggle help"},
+	{Name: "quit", Aliases: []string{"q"}, Description: "Quit"},
+}
+
+// commandHelp lists the commands of the : prompt for the help screen
+func commandHelp() string {
+	var b strings.Builder
+	for _, spec := range commandSpecs {
+		usage := ":" + spec.Name
+		if spec.Args != "" {
+			usage += " " + spec.Args
+		}
+		b.WriteString(fmt.Sprintf("  %-20s %s\n", usage, spec.Description))
+	}
+	return b.String()
+}
+
+// lookupCommand finds a command by name or alias
+func lookupCommand(name string) (commandSpec, bool) {
+	for _, spec := range commandSpecs {
+		if spec.Name == name {
+			return spec, true
+		}
+		for _, alias := range spec.Aliases {
+			if alias == name {
+				return spec, true
+			}
+		}
+	}
+	return commandSpec{}, false
+}
+
+// completeCommand completes the last word of a command line. Command names are
+// completed in the first word and repository names after "repo". It returns the
+// completed line and, when the completion is ambiguous, the candidates.
+func completeCommand(line string, repositories []string) (string, []string) {
+	fields := strings.Fields(line)
+	endsWithSpace := strings.HasSuffix(line, " ")
+
+	var word string
+	var candidates []string
+	switch {
+	case len(fields) == 0 || (len(fields) == 1 && !endsWithSpace):
+		if len(fields) == 1 {
+			word = fields[0]
+		}
+		for _, spec := range commandSpecs {
+			candidates = append(candidates, spec.Name)
+		}
+	case fields[0] == "repo" && (len(fields) == 1 || (len(fields) == 2 && !endsWithSpace)):
+		if len(fields) == 2 {
+			word = fields[1]
+		}
+		candidates = repositories
+	default:
+		return line, nil
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(word)) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return line, nil
+	}
+
+	prefix := strings.TrimSuffix(line, word)
+	if len(matches) == 1 {
+		return prefix + matches[0] + " ", nil
+	}
+
+	sort.Strings(matches)
+	completed := commonPrefix(matches)
+	if len(completed) < len(word) {
+		completed = word
+	}
+	return prefix + completed, matches
+}
+
+// commonPrefix returns the longest prefix shared by all values
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// completeCommandInput completes the command prompt with Tab
+func (app *BubbleApp) completeCommandInput() (tea.Model, tea.Cmd) {
+	line, suggestions := completeCommand(app.viewManager.GetCommandInput(), app.monitor.GetCachedRepositoryNames())
+	app.viewManager.SetCommandInput(line)
+	app.viewManager.SetCommandSuggestions(suggestions)
+	return app, nil
+}
+
+// executeCommand runs a line entered at the : prompt
+func (app *BubbleApp) executeCommand(line string) (tea.Model, tea.Cmd) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return app, nil
+	}
+
+	spec, ok := lookupCommand(fields[0])
+	if !ok {
+		app.errorMsg = fmt.Sprintf("Unknown command: %s", fields[0])
+		return app, nil
+	}
+	args := fields[1:]
+	app.errorMsg = ""
+
+	switch spec.Name {
+	case "pending":
+		return app.switchToView(ViewPending)
+
+	case "recent":
+		return app.switchToView(ViewRecent)
+
+	case "repo":
+		if len(args) == 0 {
+			app.viewManager.SetRepositoryScope("")
+			return app, nil
+		}
+		if !app.isKnownRepository(args[0]) {
+			app.errorMsg = fmt.Sprintf("Unknown repository: %s", args[0])
+			return app, nil
+		}
+		app.viewManager.SetRepositoryScope(args[0])
+		return app, nil
+
+	case "org":
+		if len(args) != 1 {
+			app.errorMsg = "Usage: :org <name>"
+			return app, nil
+		}
+		app.loading = true
+		return app, app.commandHandler.SwitchOrganization(app.ctx, args[0])
+
+	case "filter":
+		if err := app.viewManager.SetFilter(strings.Join(args, " ")); err != nil {
+			app.errorMsg = err.Error()
+		}
+		return app, nil
+
+	case "approve":
+		if app.viewManager.GetCurrentView() != ViewPending {
+			app.errorMsg = "Approve is only available in Approval Waiting Jobs"
+			return app, nil
+		}
+		return app.showApprovalConfirmation()
+
+	case "reject":
+		if app.viewManager.GetCurrentView() != ViewPending {
+			app.errorMsg = "Reject is only available in Approval Waiting Jobs"
+			return app, nil
+		}
+		return app.showRejectionConfirmation()
+
+	case "cancel":
+		return app.showCancelConfirmation()
+
+	case "refresh":
+		return app.refreshCurrentView()
+
+	case "help":
+		app.showHelp = !app.showHelp
+		return app, nil
+
+	case "quit":
+		app.cancel()
+		return app, tea.Quit
+	}
+
+	return app, nil
+}
+
+// switchToView shows a view, loading recent jobs when switching to them
+func (app *BubbleApp) switchToView(view ViewType) (tea.Model, tea.Cmd) {
+	if app.viewManager.GetCurrentView() == view {
+		return app, nil
+	}
+	return app.toggleView()
+}
+
+// isKnownRepository reports whether a repository is in the cached repository list.
+// Any name is accepted before the list has been fetched.
+func (app *BubbleApp) isKnownRepository(name string) bool {
+	repositories := app.monitor.GetCachedRepositoryNames()
+	if len(repositories) == 0 {
+		return true
+	}
+	for _, repo := range repositories {
+		if repo == name {
+			return true
+		}
+	}
+	return false
+}
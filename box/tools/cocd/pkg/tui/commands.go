@@ -2,19 +2,30 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v60/github"
+	githubclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/history"
 	"github.com/younsl/cocd/pkg/monitor"
 	"github.com/younsl/cocd/pkg/scanner"
+	"github.com/younsl/cocd/pkg/workflow"
 )
 
 // CommandHandler handles all command operations
 type CommandHandler struct {
 	monitor Monitor
 	config  *AppConfig
+	
+	// service is rebuilt whenever the monitor switches to a new client
+	serviceMu     sync.Mutex
+	service       *workflow.Service
+	serviceClient *githubclient.Client
 }
 
 // NewCommandHandler creates a new command handler
@@ -25,19 +36,30 @@ func NewCommandHandler(monitor Monitor, config *AppConfig) CommandHandlerInterfa
 	}
 }
 
-func (ch *CommandHandler) generateApprovalMessage() string {
-	timezone := ch.config.Timezone
-	if timezone == "" {
-		timezone = "UTC"
+// ReviewMessage renders the comment sent to GitHub when approving or rejecting a job
+func (ch *CommandHandler) ReviewMessage(action string, job scanner.JobStatus, comment string) string {
+	service, err := ch.workflowService()
+	if err != nil {
+		return ""
 	}
+	return service.ReviewComment(context.Background(), action, job, comment)
+}
+
+// workflowService returns the approval service for the monitor's current client
+func (ch *CommandHandler) workflowService() (*workflow.Service, error) {
+	ch.serviceMu.Lock()
+	defer ch.serviceMu.Unlock()
 	
-	loc, err := time.LoadLocation(timezone)
-	if err != nil {
-		loc = time.UTC
+	client, ok := ch.monitor.GetClient().(*githubclient.Client)
+	if !ok || client == nil {
+		return nil, fmt.Errorf("GitHub client not available")
 	}
 	
-	timestamp := time.Now().In(loc).Format("2006-01-02 15:04:05 MST")
-	return fmt.Sprintf("Remote approved by cocd at %s", timestamp)
+	if ch.service == nil || ch.serviceClient != client {
+		ch.service = workflow.NewService(client, ch.config.CommentTemplate, ch.config.Timezone)
+		ch.serviceClient = client
+	}
+	return ch.service, nil
 }
 
 func (ch *CommandHandler) StartMonitoring(ctx context.Context, jobsChan chan []scanner.JobStatus) tea.Cmd {
@@ -60,22 +82,26 @@ func (ch *CommandHandler) StartMonitoring(ctx context.Context, jobsChan chan []s
 }
 
 func (ch *CommandHandler) LoadPendingJobs(ctx context.Context) tea.Cmd {
+	org := ch.config.Org
 	return tea.Cmd(func() tea.Msg {
 		jobs, err := ch.monitor.GetPendingJobs(ctx)
 		if err != nil {
 			return errorMsg(err.Error())
 		}
+		ch.recordJobs(org, jobs)
 		return pendingJobsMsg(jobs)
 	})
 }
 
 
 func (ch *CommandHandler) LoadRecentJobs(ctx context.Context) tea.Cmd {
+	org := ch.config.Org
 	return tea.Cmd(func() tea.Msg {
 		jobs, err := ch.monitor.GetRecentJobs(ctx)
 		if err != nil {
 			return errorMsg(err.Error())
 		}
+		ch.recordJobs(org, jobs)
 		nextScanAt := time.Now().Add(30 * time.Second)
 		ch.monitor.GetProgressTracker().SetNextScanTimer(nextScanAt, 1, false)
 		return recentJobsMsg(jobs)
@@ -83,6 +109,7 @@ func (ch *CommandHandler) LoadRecentJobs(ctx context.Context) tea.Cmd {
 }
 
 func (ch *CommandHandler) LoadRecentJobsStreaming(ctx context.Context, updateChan chan<- tea.Msg) tea.Cmd {
+	org := ch.config.Org
 	return tea.Cmd(func() tea.Msg {
 		jobUpdateChan := make(chan monitor.JobUpdate, 100)
 		
@@ -100,13 +127,17 @@ func (ch *CommandHandler) LoadRecentJobsStreaming(ctx context.Context, updateCha
 		}()
 		
 		go func() {
+			// Recorded once the scan is over, in one write instead of one per repository
+			var scanned []scanner.JobStatus
 			for update := range jobUpdateChan {
+				scanned = append(scanned, update.Jobs...)
 				select {
 				case updateChan <- recentJobUpdateMsg(update):
 				case <-ctx.Done():
 					return
 				}
 			}
+			ch.recordJobs(org, scanned)
 		}()
 		
 		return scanProgressMsg{}
@@ -119,20 +150,13 @@ func (ch *CommandHandler) TickCmd() tea.Cmd {
 	})
 }
 
-func (ch *CommandHandler) JumpToActions(vm ViewManagerInterface, jobs, recentJobs []scanner.JobStatus) tea.Cmd {
+// JumpToActions opens the Actions page of the job under the cursor among the visible jobs
+func (ch *CommandHandler) JumpToActions(vm ViewManagerInterface, visibleJobs []scanner.JobStatus) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		var selectedJob *scanner.JobStatus
 		
-		if vm.GetCurrentView() == ViewPending {
-				combinedJobs := vm.GetCombinedPendingJobs(jobs)
-			if len(combinedJobs) > 0 && vm.GetCursor() < len(combinedJobs) {
-				selectedJob = &combinedJobs[vm.GetCursor()]
-			}
-		} else if vm.GetCurrentView() == ViewRecent {
-			visibleJobs := vm.GetPaginatedJobs(recentJobs)
-			if len(visibleJobs) > 0 && vm.GetCursor() < len(visibleJobs) {
-				selectedJob = &visibleJobs[vm.GetCursor()]
-			}
+		if len(visibleJobs) > 0 && vm.GetCursor() < len(visibleJobs) {
+			selectedJob = &visibleJobs[vm.GetCursor()]
 		}
 		
 		if selectedJob != nil {
@@ -146,6 +170,27 @@ func (ch *CommandHandler) JumpToActions(vm ViewManagerInterface, jobs, recentJob
 	})
 }
 
+// OpenWorkflowJob opens the job selected in the detail pane, or the run when no job is loaded
+func (ch *CommandHandler) OpenWorkflowJob(vm ViewManagerInterface) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		run := vm.GetDetailTargetJob()
+		if run == nil {
+			return nil
+		}
+		
+		url := run.GetActionsURL(ch.config.ServerURL, ch.config.Org)
+		jobs := vm.GetDetailJobs()
+		if cursor := vm.GetDetailCursor(); cursor < len(jobs) && jobs[cursor].GetHTMLURL() != "" {
+			url = jobs[cursor].GetHTMLURL()
+		}
+		
+		if err := OpenURL(url); err != nil {
+			return errorMsg(fmt.Sprintf("Failed to open browser: %v", err))
+		}
+		return nil
+	})
+}
+
 func (ch *CommandHandler) InitializeTimer() {
 	nextScanAt := time.Now().Add(10 * time.Second)
 	ch.monitor.GetProgressTracker().SetNextScanTimer(nextScanAt, 1, false)
@@ -170,6 +215,78 @@ func (ch *CommandHandler) DelayedRefresh(delay time.Duration) tea.Cmd {
 	})
 }
 
+// SwitchOrganization points the monitor at another organization
+func (ch *CommandHandler) SwitchOrganization(ctx context.Context, org string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if err := ch.monitor.SwitchOrganization(ctx, org); err != nil {
+			return errorMsg(fmt.Sprintf("Failed to switch to organization %s: %v", org, err))
+		}
+		return organizationSwitchedMsg(org)
+	})
+}
+
+// BulkApprove approves, for every run, the pending environments the current user can approve
+func (ch *CommandHandler) BulkApprove(ctx context.Context, jobs []scanner.JobStatus, comment string) tea.Cmd {
+	return ch.runBulk(BulkApprove, jobs, func(service *workflow.Service, job scanner.JobStatus) (string, error) {
+		approvable, err := service.ApprovableDeployments(ctx, job, "")
+		if err != nil {
+			return "", err
+		}
+		if len(approvable) == 0 {
+			return "", fmt.Errorf("no pending environment you can approve")
+		}
+		
+		if err := service.Approve(ctx, job, workflow.EnvironmentIDs(approvable), comment); err != nil {
+			return "", err
+		}
+		environments := strings.Join(workflow.EnvironmentNames(approvable), ", ")
+		ch.recordReview(history.KindApproved, job, environments)
+		return "approved " + environments, nil
+	})
+}
+
+// BulkCancel cancels every run
+func (ch *CommandHandler) BulkCancel(ctx context.Context, jobs []scanner.JobStatus) tea.Cmd {
+	return ch.runBulk(BulkCancel, jobs, func(service *workflow.Service, job scanner.JobStatus) (string, error) {
+		if err := service.Cancel(ctx, job); err != nil {
+			if errors.Is(err, workflow.ErrCancelInProgress) {
+				return err.Error(), nil
+			}
+			return "", err
+		}
+		return "cancelled", nil
+	})
+}
+
+// runBulk applies an action to runs concurrently and collects a result per run in input order
+func (ch *CommandHandler) runBulk(action string, jobs []scanner.JobStatus, apply func(service *workflow.Service, job scanner.JobStatus) (string, error)) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		service, err := ch.workflowService()
+		if err != nil {
+			return errorMsg(err.Error())
+		}
+		
+		results := make([]BulkResult, len(jobs))
+		semaphore := make(chan struct{}, bulkConcurrency)
+		var wg sync.WaitGroup
+		
+		for i, job := range jobs {
+			wg.Add(1)
+			go func(i int, job scanner.JobStatus) {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+				
+				detail, err := apply(service, job)
+				results[i] = BulkResult{Job: job, Detail: detail, Err: err}
+			}(i, job)
+		}
+		wg.Wait()
+		
+		return bulkResultMsg{action: action, results: results}
+	})
+}
+
 func (ch *CommandHandler) CancelWorkflow(ctx context.Context, vm ViewManagerInterface) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		job := vm.GetCancelTargetJob()
@@ -177,6 +294,27 @@ func (ch *CommandHandler) CancelWorkflow(ctx context.Context, vm ViewManagerInte
 			return errorMsg("No job selected for cancellation")
 		}
 		
+		service, err := ch.workflowService()
+		if err != nil {
+			return errorMsg(err.Error())
+		}
+		
+		if err := service.Cancel(ctx, *job); err != nil {
+			// Cancellation of already scheduled jobs is still processing on GitHub's side
+			if errors.Is(err, workflow.ErrCancelInProgress) {
+				// Return processing message to trigger delayed refresh
+				return cancelProcessingMsg{job: job}
+			}
+			return errorMsg(fmt.Sprintf("Failed to cancel workflow: %v", err))
+		}
+		
+		return cancelSuccessMsg{}
+	})
+}
+
+// LoadWorkflowJobs fetches the jobs and steps of a workflow run for the detail view
+func (ch *CommandHandler) LoadWorkflowJobs(ctx context.Context, job scanner.JobStatus) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
 		clientInterface := ch.monitor.GetClient()
 		if clientInterface == nil {
 			return errorMsg("GitHub client not available")
@@ -187,27 +325,25 @@ func (ch *CommandHandler) CancelWorkflow(ctx context.Context, vm ViewManagerInte
 			return errorMsg("Failed to create GitHub client adapter")
 		}
 		
-		_, err := client.CancelWorkflowRun(ctx, job.Repository, job.RunID)
+		opts := &github.ListWorkflowJobsOptions{
+			Filter: "latest",
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+		
+		jobs, _, err := client.ListWorkflowJobs(ctx, job.Repository, job.RunID, opts)
 		if err != nil {
-			// Silently handle "job scheduled" error - it usually means cancellation is processing
-			if strings.Contains(err.Error(), "job scheduled on GitHub side") {
-				// Return processing message to trigger delayed refresh
-				return cancelProcessingMsg{job: job}
-			}
-			return errorMsg(fmt.Sprintf("Failed to cancel workflow: %v", err))
+			return errorMsg(fmt.Sprintf("Failed to list workflow jobs: %v", err))
 		}
 		
-		return cancelSuccessMsg{}
+		return workflowJobsMsg{runID: job.RunID, jobs: jobs.Jobs}
 	})
 }
 
-func (ch *CommandHandler) ApproveDeployment(ctx context.Context, vm ViewManagerInterface) tea.Cmd {
+// LoadJobLogs downloads the logs of a workflow job for the log viewer
+func (ch *CommandHandler) LoadJobLogs(ctx context.Context, job scanner.JobStatus, jobID int64) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		job := vm.GetApprovalTargetJob()
-		if job == nil {
-			return errorMsg("No job selected for approval")
-		}
-		
 		clientInterface := ch.monitor.GetClient()
 		if clientInterface == nil {
 			return errorMsg("GitHub client not available")
@@ -218,31 +354,105 @@ func (ch *CommandHandler) ApproveDeployment(ctx context.Context, vm ViewManagerI
 			return errorMsg("Failed to create GitHub client adapter")
 		}
 		
-		pendingDeployments, _, err := client.GetPendingDeployments(ctx, job.Repository, job.RunID)
+		workflowJob, _, err := client.GetWorkflowJob(ctx, job.Repository, jobID)
 		if err != nil {
-			return errorMsg(fmt.Sprintf("Failed to get pending deployments: %v", err))
+			return errorMsg(fmt.Sprintf("Failed to get workflow job: %v", err))
+		}
+		if workflowJob.GetStatus() != "completed" {
+			return errorMsg(fmt.Sprintf("Logs for %s are available once the job completes (status: %s)",
+				workflowJob.GetName(), workflowJob.GetStatus()))
 		}
 		
-		if len(pendingDeployments) == 0 {
-			return errorMsg("No pending deployments found for this workflow")
+		content, _, err := client.GetWorkflowJobLogs(ctx, job.Repository, jobID)
+		if err != nil {
+			return errorMsg(fmt.Sprintf("Failed to download job logs: %v", err))
 		}
 		
-		var environmentIDs []int64
-		for _, pd := range pendingDeployments {
-			if pd.Environment.ID != nil {
-				environmentIDs = append(environmentIDs, *pd.Environment.ID)
+		return jobLogsMsg{jobID: jobID, content: content}
+	})
+}
+
+// LoadPendingDeployments fetches the environments a run is waiting on for the approval popup
+func (ch *CommandHandler) LoadPendingDeployments(ctx context.Context, job scanner.JobStatus) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		service, err := ch.workflowService()
+		if err != nil {
+			return errorMsg(err.Error())
+		}
+		
+		pendingDeployments, err := service.PendingDeployments(ctx, job)
+		if err != nil {
+			if errors.Is(err, workflow.ErrNoPendingDeployments) {
+				return errorMsg("No pending deployments found for this workflow")
 			}
+			return errorMsg(fmt.Sprintf("Failed to get pending deployments: %v", err))
+		}
+		
+		return pendingDeploymentsMsg{runID: job.RunID, deployments: pendingDeployments}
+	})
+}
+
+func (ch *CommandHandler) ApproveDeployment(ctx context.Context, vm ViewManagerInterface) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		job := vm.GetApprovalTargetJob()
+		if job == nil {
+			return errorMsg("No job selected for approval")
 		}
 		
+		if vm.GetApprovalEnvironments() == nil {
+			return errorMsg("Pending environments are still loading, try again")
+		}
+		
+		environmentIDs := vm.GetSelectedEnvironmentIDs()
 		if len(environmentIDs) == 0 {
-			return errorMsg("No environment IDs found in pending deployments")
+			return errorMsg("No environments selected for approval")
 		}
 		
-		_, err = client.ApprovePendingDeployment(ctx, job.Repository, job.RunID, environmentIDs, ch.generateApprovalMessage())
+		service, err := ch.workflowService()
 		if err != nil {
+			return errorMsg(err.Error())
+		}
+		
+		if err := service.Approve(ctx, *job, environmentIDs, vm.GetComment()); err != nil {
 			return errorMsg(fmt.Sprintf("Failed to approve deployment: %v", err))
 		}
+		ch.recordReview(history.KindApproved, *job, strings.Join(selectedEnvironmentNames(vm), ", "))
 		
 		return approvalSuccessMsg{}
 	})
+}
+
+// selectedEnvironmentNames returns the names of the environments checked in the approval popup
+func selectedEnvironmentNames(vm ViewManagerInterface) []string {
+	var names []string
+	for i, pd := range vm.GetApprovalEnvironments() {
+		if vm.IsApprovalEnvironmentSelected(i) {
+			names = append(names, pd.EnvironmentName())
+		}
+	}
+	return names
+}
+
+func (ch *CommandHandler) RejectDeployment(ctx context.Context, vm ViewManagerInterface) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		job := vm.GetRejectionTargetJob()
+		if job == nil {
+			return errorMsg("No job selected for rejection")
+		}
+		
+		service, err := ch.workflowService()
+		if err != nil {
+			return errorMsg(err.Error())
+		}
+		
+		if err := service.Reject(ctx, *job, vm.GetComment()); err != nil {
+			if errors.Is(err, workflow.ErrNoPendingDeployments) {
+				return errorMsg("No pending deployments found for this workflow")
+			}
+			return errorMsg(fmt.Sprintf("Failed to reject deployment: %v", err))
+		}
+		ch.recordReview(history.KindRejected, *job, vm.GetComment())
+		
+		return rejectionSuccessMsg{}
+	})
 }
\ No newline at end of file
@@ -1,5 +1,7 @@
 package tui
 
+import "github.com/younsl/cocd/pkg/history"
+
 // AppConfig holds configuration for the TUI application
 type AppConfig struct {
 	ServerURL   string
@@ -8,4 +10,10 @@ type AppConfig struct {
 	Token       string
 	Timezone    string
 	Version     string
+	
+	// CommentTemplate is the review comment template for approvals and rejections
+	CommentTemplate string
+	
+	// History saves the runs seen and the reviews made, nil when disabled
+	History *history.Store
 }
\ No newline at end of file
@@ -7,12 +7,13 @@ import (
 	githubclient "github.com/younsl/cocd/pkg/github"
 )
 
-// GitHubClient defines the interface for GitHub operations
+// GitHubClient defines the interface for GitHub operations. Approvals, rejections
+// and cancellations go through workflow.Service instead.
 type GitHubClient interface {
-	CancelWorkflowRun(ctx context.Context, repo string, runID int64) (*github.Response, error)
-	GetPendingDeployments(ctx context.Context, repo string, runID int64) ([]*githubclient.PendingDeployment, *github.Response, error)
-	ApprovePendingDeployment(ctx context.Context, repo string, runID int64, environmentIDs []int64, comment string) (*github.Response, error)
 	GetWorkflowRun(ctx context.Context, repo string, runID int64) (*github.WorkflowRun, *github.Response, error)
+	ListWorkflowJobs(ctx context.Context, repo string, runID int64, opts *github.ListWorkflowJobsOptions) (*github.Jobs, *github.Response, error)
+	GetWorkflowJob(ctx context.Context, repo string, jobID int64) (*github.WorkflowJob, *github.Response, error)
+	GetWorkflowJobLogs(ctx context.Context, repo string, jobID int64) (string, *github.Response, error)
 }
 
 // GitHubClientAdapter adapts the internal GitHub client to our interface
@@ -28,18 +29,18 @@ func NewGitHubClientAdapter(client interface{}) GitHubClient {
 	return nil
 }
 
-func (gca *GitHubClientAdapter) CancelWorkflowRun(ctx context.Context, repo string, runID int64) (*github.Response, error) {
-	return gca.client.CancelWorkflowRun(ctx, repo, runID)
+func (gca *GitHubClientAdapter) GetWorkflowRun(ctx context.Context, repo string, runID int64) (*github.WorkflowRun, *github.Response, error) {
+	return gca.client.GetWorkflowRun(ctx, repo, runID)
 }
 
-func (gca *GitHubClientAdapter) GetPendingDeployments(ctx context.Context, repo string, runID int64) ([]*githubclient.PendingDeployment, *github.Response, error) {
-	return gca.client.GetPendingDeployments(ctx, repo, runID)
+func (gca *GitHubClientAdapter) ListWorkflowJobs(ctx context.Context, repo string, runID int64, opts *github.ListWorkflowJobsOptions) (*github.Jobs, *github.Response, error) {
+	return gca.client.ListWorkflowJobs(ctx, repo, runID, opts)
 }
 
-func (gca *GitHubClientAdapter) ApprovePendingDeployment(ctx context.Context, repo string, runID int64, environmentIDs []int64, comment string) (*github.Response, error) {
-	return gca.client.ApprovePendingDeployment(ctx, repo, runID, environmentIDs, comment)
+func (gca *GitHubClientAdapter) GetWorkflowJob(ctx context.Context, repo string, jobID int64) (*github.WorkflowJob, *github.Response, error) {
+	return gca.client.GetWorkflowJob(ctx, repo, jobID)
 }
 
-func (gca *GitHubClientAdapter) GetWorkflowRun(ctx context.Context, repo string, runID int64) (*github.WorkflowRun, *github.Response, error) {
-	return gca.client.GetWorkflowRun(ctx, repo, runID)
-}
\ No newline at end of file
+func (gca *GitHubClientAdapter) GetWorkflowJobLogs(ctx context.Context, repo string, jobID int64) (string, *github.Response, error) {
+	return gca.client.GetWorkflowJobLogs(ctx, repo, jobID)
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+const (
+	// historyLoadLimit is how many of the saved runs are shown at startup
+	historyLoadLimit = 500
+	// historyCompletedWindow is how long saved runs that waited for an approval
+	// stay in the pending view after they finished
+	historyCompletedWindow = 24 * time.Hour
+)
+
+// LoadHistory reads the runs of the organization saved by earlier sessions
+func (ch *CommandHandler) LoadHistory() tea.Cmd {
+	store := ch.config.History
+	if store == nil {
+		return nil
+	}
+
+	org := ch.config.Org
+	return tea.Cmd(func() tea.Msg {
+		runs, err := store.Runs(org, historyLoadLimit)
+		if err != nil {
+			return nil
+		}
+		return historyMsg{org: org, runs: runs}
+	})
+}
+
+// recordJobs saves the runs of a scan in the history. The history is best
+// effort, failing to write it must not get in the way of monitoring.
+func (ch *CommandHandler) recordJobs(org string, jobs []scanner.JobStatus) {
+	if ch.config.History != nil {
+		_ = ch.config.History.Record(org, jobs, time.Now())
+	}
+}
+
+// recordReview saves an approval or rejection made from the TUI in the history
+func (ch *CommandHandler) recordReview(kind string, job scanner.JobStatus, detail string) {
+	if ch.config.History != nil {
+		_ = ch.config.History.RecordReview(ch.config.Org, kind, job, detail, time.Now())
+	}
+}
+
+// handleHistoryMessage shows the saved runs until the first scan replaces them
+func (app *BubbleApp) handleHistoryMessage(msg historyMsg) (tea.Model, tea.Cmd) {
+	// The organization was switched or a scan was faster than the history
+	if msg.org != app.config.Org || len(app.recentJobs) > 0 {
+		return app, nil
+	}
+
+	var recent, completed []scanner.JobStatus
+	for _, run := range msg.runs {
+		recent = append(recent, run.Job)
+
+		job := run.Job
+		active := job.Status == "waiting" || job.Status == "queued" || job.Status == "in_progress"
+		if run.Waited && !active && job.CompletedAt != nil && time.Since(*job.CompletedAt) < historyCompletedWindow {
+			completed = append(completed, job)
+		}
+	}
+
+	app.recentJobs = recent
+	app.viewManager.RestoreCompletedJobs(completed)
+	return app, nil
+}
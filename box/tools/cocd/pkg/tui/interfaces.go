@@ -5,6 +5,8 @@ import (
 	"time"
 	
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v60/github"
+	githubclient "github.com/younsl/cocd/pkg/github"
 	"github.com/younsl/cocd/pkg/monitor"
 	"github.com/younsl/cocd/pkg/scanner"
 )
@@ -20,6 +22,9 @@ type Monitor interface {
 	GetUpdateInterval() int
 	GetRecentJobsWithStreaming(ctx context.Context, jobUpdateChan chan<- monitor.JobUpdate) error
 	GetAuthenticatedUser(ctx context.Context) (string, error)
+	GetCachedRepositoryNames() []string
+	SwitchOrganization(ctx context.Context, org string) error
+	GetAPIStats() githubclient.APIStats
 }
 
 // ProgressTracker defines the interface for tracking progress
@@ -45,10 +50,85 @@ type ViewManagerInterface interface {
 	
 	// Job tracking
 	TrackCompletedJobs(currentJobs, newJobs []scanner.JobStatus)
+	RestoreCompletedJobs(jobs []scanner.JobStatus)
 	GetCombinedPendingJobs(jobs []scanner.JobStatus) []scanner.JobStatus
 	IsJobCompleted(job scanner.JobStatus) bool
 	GetMaxCursorPosition(pendingJobs, recentJobs []scanner.JobStatus) int
 	
+	// Job detail view
+	ShowJobDetail(job scanner.JobStatus)
+	HideJobDetail()
+	IsShowingJobDetail() bool
+	GetDetailTargetJob() *scanner.JobStatus
+	SetDetailJobs(jobs []*github.WorkflowJob)
+	GetDetailJobs() []*github.WorkflowJob
+	SetDetailLoading(loading bool)
+	IsDetailLoading() bool
+	GetDetailLoadedAt() time.Time
+	GetDetailRequestedAt() time.Time
+	MoveDetailCursor(direction int)
+	GetDetailCursor() int
+	
+	// Log viewer for a workflow job in the detail view
+	OpenLogViewer(title string, jobID int64, width, height int)
+	CloseLogViewer()
+	IsShowingLogViewer() bool
+	GetLogViewer() *LogViewer
+	GetLogJobID() int64
+	
+	// Job table filter
+	StartFilterEditing()
+	StopFilterEditing()
+	IsEditingFilter() bool
+	UpdateFilterInput(msg tea.Msg) tea.Cmd
+	ClearFilter()
+	SetFilter(query string) error
+	GetFilter() *scanner.Filter
+	GetFilterError() string
+	GetFilterInputView() string
+	
+	// Repository scope set with :repo
+	SetRepositoryScope(repo string)
+	GetRepositoryScope() string
+	
+	// Command prompt
+	StartCommandInput()
+	StopCommandInput()
+	IsEditingCommand() bool
+	UpdateCommandInput(msg tea.Msg) tea.Cmd
+	GetCommandInput() string
+	SetCommandInput(value string)
+	SetCommandSuggestions(suggestions []string)
+	GetCommandSuggestions() []string
+	GetCommandInputView() string
+	
+	// Multi-select for bulk actions
+	ToggleJobSelection(job scanner.JobStatus)
+	ToggleAllSelection(jobs []scanner.JobStatus)
+	ClearSelection()
+	IsJobSelected(job scanner.JobStatus) bool
+	GetSelectedJobs() []scanner.JobStatus
+	
+	// Bulk approve/cancel confirmation
+	ShowBulkConfirm(action string, jobs []scanner.JobStatus)
+	HideBulkConfirm()
+	IsShowingBulkConfirm() bool
+	GetBulkAction() string
+	GetBulkTargetJobs() []scanner.JobStatus
+	SetBulkSelection(selection int)
+	GetBulkSelection() int
+	IsBulkConfirmed() bool
+	
+	// Job table ordering
+	CycleSortKey()
+	ToggleSortDirection()
+	GetSort() (key scanner.SortKey, descending bool)
+	FocusJob(index int)
+	
+	// Approvability filter for the pending view
+	ToggleApprovableOnly()
+	IsApprovableOnly() bool
+	
 	// Cancel confirmation
 	ShowCancelConfirm(job scanner.JobStatus)
 	HideCancelConfirm()
@@ -67,6 +147,31 @@ type ViewManagerInterface interface {
 	GetApprovalSelection() int
 	IsApprovalConfirmed() bool
 	
+	// Environment checklist in the approval popup
+	SetApprovalEnvironments(deployments []*githubclient.PendingDeployment)
+	GetApprovalEnvironments() []*githubclient.PendingDeployment
+	MoveApprovalEnvironmentCursor(direction int)
+	GetApprovalEnvironmentCursor() int
+	ToggleApprovalEnvironment()
+	IsApprovalEnvironmentSelected(index int) bool
+	GetSelectedEnvironmentIDs() []int64
+	
+	// Rejection confirmation
+	ShowRejectionConfirm(job scanner.JobStatus)
+	HideRejectionConfirm()
+	IsShowingRejectionConfirm() bool
+	GetRejectionTargetJob() *scanner.JobStatus
+	SetRejectionSelection(selection int)
+	GetRejectionSelection() int
+	IsRejectionConfirmed() bool
+	
+	// Review comment input shared by the approval and rejection popups
+	IsEditingComment() bool
+	SetEditingComment(editing bool)
+	UpdateCommentInput(msg tea.Msg) tea.Cmd
+	GetComment() string
+	GetCommentInputView() string
+	
 	// Job highlighting for newly scanned jobs
 	MarkNewlyScannedJobs(jobs []scanner.JobStatus) []scanner.JobStatus
 	IsJobHighlighted(job scanner.JobStatus) bool
@@ -79,24 +184,41 @@ type CommandHandlerInterface interface {
 	LoadRecentJobs(ctx context.Context) tea.Cmd
 	LoadRecentJobsStreaming(ctx context.Context, updateChan chan<- tea.Msg) tea.Cmd
 	TickCmd() tea.Cmd
-	JumpToActions(vm ViewManagerInterface, jobs, recentJobs []scanner.JobStatus) tea.Cmd
+	JumpToActions(vm ViewManagerInterface, visibleJobs []scanner.JobStatus) tea.Cmd
+	OpenWorkflowJob(vm ViewManagerInterface) tea.Cmd
 	InitializeTimer()
 	UpdateTimerForView(viewType ViewType)
 	CancelWorkflow(ctx context.Context, vm ViewManagerInterface) tea.Cmd
+	LoadPendingDeployments(ctx context.Context, job scanner.JobStatus) tea.Cmd
+	LoadWorkflowJobs(ctx context.Context, job scanner.JobStatus) tea.Cmd
+	LoadJobLogs(ctx context.Context, job scanner.JobStatus, jobID int64) tea.Cmd
 	ApproveDeployment(ctx context.Context, vm ViewManagerInterface) tea.Cmd
+	RejectDeployment(ctx context.Context, vm ViewManagerInterface) tea.Cmd
+	ReviewMessage(action string, job scanner.JobStatus, comment string) string
 	DelayedRefresh(delay time.Duration) tea.Cmd
+	SwitchOrganization(ctx context.Context, org string) tea.Cmd
+	BulkApprove(ctx context.Context, jobs []scanner.JobStatus, comment string) tea.Cmd
+	BulkCancel(ctx context.Context, jobs []scanner.JobStatus) tea.Cmd
+	LoadHistory() tea.Cmd
 }
 
 // UIRenderer defines the interface for rendering UI components
 type UIRenderer interface {
 	RenderHeader(monitor Monitor) string
 	RenderViewSelector(currentView ViewType, pendingCount, recentCount int, vm ViewManagerInterface) string
+	RenderFilterBar(vm ViewManagerInterface) string
+	RenderCommandBar(vm ViewManagerInterface) string
 	RenderJobTable(jobs []scanner.JobStatus, cursor int, vm ViewManagerInterface) string
 	RenderStatus(errorMsg string) string
 	RenderPagination(currentView ViewType, vm ViewManagerInterface, totalJobs int, jobs []scanner.JobStatus) string
 	RenderHelp(monitor Monitor) string
 	RenderCancelConfirm(job scanner.JobStatus, selection int) string
-	RenderApprovalConfirm(job scanner.JobStatus, selection int) string
+	RenderApprovalConfirm(popup ReviewPopup) string
+	RenderRejectionConfirm(popup ReviewPopup) string
+	RenderBulkConfirm(popup BulkPopup) string
+	RenderBulkResults(results []BulkResult) string
+	RenderJobDetail(detail JobDetail) string
+	RenderLogViewer(viewer *LogViewer) string
 }
 
 // KeyHandler defines the interface for handling keyboard input
@@ -107,6 +229,8 @@ type KeyHandler interface {
 // JobService defines the interface for job-related operations
 type JobService interface {
 	GetJobsForView(view ViewType, pendingJobs, recentJobs []scanner.JobStatus, vm ViewManagerInterface) []scanner.JobStatus
+	FilterJobs(view ViewType, jobs []scanner.JobStatus, vm ViewManagerInterface) []scanner.JobStatus
+	ListJobsForView(view ViewType, pendingJobs, recentJobs []scanner.JobStatus, vm ViewManagerInterface) []scanner.JobStatus
 	RefreshJobs(ctx context.Context, view ViewType) tea.Cmd
 	RefreshJobsWithStreaming(ctx context.Context, view ViewType, updateChan chan<- tea.Msg) tea.Cmd
 }
\ No newline at end of file
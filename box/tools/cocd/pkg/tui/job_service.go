@@ -23,16 +23,81 @@ func NewJobService(commands CommandHandlerInterface) JobService {
 func (js *DefaultJobService) GetJobsForView(view ViewType, pendingJobs, recentJobs []scanner.JobStatus, vm ViewManagerInterface) []scanner.JobStatus {
 	switch view {
 	case ViewPending:
+		if vm.IsApprovableOnly() {
+			pendingJobs = filterApprovableJobs(pendingJobs)
+		}
 		highlightedPendingJobs := vm.MarkNewlyScannedJobs(pendingJobs)
-		return vm.GetCombinedPendingJobs(highlightedPendingJobs)
+		return sortJobs(matchJobs(vm.GetCombinedPendingJobs(highlightedPendingJobs), vm), vm)
 	case ViewRecent:
 		highlightedRecentJobs := vm.MarkNewlyScannedJobs(recentJobs)
-		return vm.GetPaginatedJobs(highlightedRecentJobs)
+		return vm.GetPaginatedJobs(sortJobs(matchJobs(highlightedRecentJobs, vm), vm))
 	default:
 		return []scanner.JobStatus{}
 	}
 }
 
+// ListJobsForView returns every job of a view in display order, before pagination and
+// without touching the newly scanned highlighting
+func (js *DefaultJobService) ListJobsForView(view ViewType, pendingJobs, recentJobs []scanner.JobStatus, vm ViewManagerInterface) []scanner.JobStatus {
+	switch view {
+	case ViewPending:
+		if vm.IsApprovableOnly() {
+			pendingJobs = filterApprovableJobs(pendingJobs)
+		}
+		return sortJobs(matchJobs(vm.GetCombinedPendingJobs(pendingJobs), vm), vm)
+	case ViewRecent:
+		return sortJobs(matchJobs(recentJobs, vm), vm)
+	default:
+		return []scanner.JobStatus{}
+	}
+}
+
+// FilterJobs returns the jobs of a view that pass the active filters, before pagination
+func (js *DefaultJobService) FilterJobs(view ViewType, jobs []scanner.JobStatus, vm ViewManagerInterface) []scanner.JobStatus {
+	if view == ViewPending && vm.IsApprovableOnly() {
+		jobs = filterApprovableJobs(jobs)
+	}
+	return matchJobs(jobs, vm)
+}
+
+// matchJobs keeps the jobs inside the repository scope that match the filter
+func matchJobs(jobs []scanner.JobStatus, vm ViewManagerInterface) []scanner.JobStatus {
+	if repo := vm.GetRepositoryScope(); repo != "" {
+		scoped := make([]scanner.JobStatus, 0, len(jobs))
+		for _, job := range jobs {
+			if job.Repository == repo {
+				scoped = append(scoped, job)
+			}
+		}
+		jobs = scoped
+	}
+	return vm.GetFilter().Apply(jobs)
+}
+
+// sortJobs returns a copy of jobs ordered by the sort key selected in the view manager
+func sortJobs(jobs []scanner.JobStatus, vm ViewManagerInterface) []scanner.JobStatus {
+	key, descending := vm.GetSort()
+	if key == scanner.SortByDefault {
+		return jobs
+	}
+	
+	sorted := make([]scanner.JobStatus, len(jobs))
+	copy(sorted, jobs)
+	scanner.SortJobs(sorted, key, descending)
+	return sorted
+}
+
+// filterApprovableJobs drops waiting runs the current user is known not to be able to approve
+func filterApprovableJobs(jobs []scanner.JobStatus) []scanner.JobStatus {
+	filtered := make([]scanner.JobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		if job.IsApprovable() {
+			filtered = append(filtered, job)
+		}
+	}
+	return filtered
+}
+
 // RefreshJobs refreshes jobs for the current view
 func (js *DefaultJobService) RefreshJobs(ctx context.Context, view ViewType) tea.Cmd {
 	if view == ViewPending {
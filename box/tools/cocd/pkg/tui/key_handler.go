@@ -23,6 +23,16 @@ func (kh *DefaultKeyHandler) HandleKeyPress(msg tea.KeyMsg, app *BubbleApp) (tea
 		return kh.handleApprovalConfirmKeys(msg, app)
 	}
 	
+	// Handle rejection confirmation popup keys
+	if app.viewManager.IsShowingRejectionConfirm() {
+		return kh.handleRejectionConfirmKeys(msg, app)
+	}
+	
+	// Handle bulk confirmation popup keys
+	if app.viewManager.IsShowingBulkConfirm() {
+		return kh.handleBulkConfirmKeys(msg, app)
+	}
+	
 	// Handle cancel confirmation popup keys next
 	if app.viewManager.IsShowingCancelConfirm() {
 		return kh.handleCancelConfirmKeys(msg, app)
@@ -33,12 +43,38 @@ func (kh *DefaultKeyHandler) HandleKeyPress(msg tea.KeyMsg, app *BubbleApp) (tea
 		return kh.handleHelpKeys(msg, app)
 	}
 	
+	// Handle log viewer keys before the job detail pane it was opened from
+	if viewer := app.viewManager.GetLogViewer(); viewer != nil {
+		return kh.handleLogViewerKeys(msg, app, viewer)
+	}
+	
+	// Handle job detail pane keys
+	if app.viewManager.IsShowingJobDetail() {
+		return kh.handleJobDetailKeys(msg, app)
+	}
+	
 	// Handle main view keys
 	return kh.handleMainViewKeys(msg, app)
 }
 
 func (kh *DefaultKeyHandler) handleApprovalConfirmKeys(msg tea.KeyMsg, app *BubbleApp) (tea.Model, tea.Cmd) {
+	if app.viewManager.IsEditingComment() {
+		return kh.handleCommentInputKeys(msg, app, app.viewManager.HideApprovalConfirm)
+	}
+	
 	switch msg.String() {
+	case "tab":
+		app.viewManager.SetEditingComment(true)
+		return app, nil
+	case "up", "k":
+		app.viewManager.MoveApprovalEnvironmentCursor(-1)
+		return app, nil
+	case "down", "j":
+		app.viewManager.MoveApprovalEnvironmentCursor(1)
+		return app, nil
+	case " ":
+		app.viewManager.ToggleApprovalEnvironment()
+		return app, nil
 	case "left":
 		app.viewManager.SetApprovalSelection(0)
 		return app, nil
@@ -64,6 +100,100 @@ func (kh *DefaultKeyHandler) handleApprovalConfirmKeys(msg tea.KeyMsg, app *Bubb
 	}
 }
 
+func (kh *DefaultKeyHandler) handleRejectionConfirmKeys(msg tea.KeyMsg, app *BubbleApp) (tea.Model, tea.Cmd) {
+	if app.viewManager.IsEditingComment() {
+		return kh.handleCommentInputKeys(msg, app, app.viewManager.HideRejectionConfirm)
+	}
+	
+	switch msg.String() {
+	case "tab":
+		app.viewManager.SetEditingComment(true)
+		return app, nil
+	case "left":
+		app.viewManager.SetRejectionSelection(0)
+		return app, nil
+	case "right":
+		app.viewManager.SetRejectionSelection(1)
+		return app, nil
+	case "enter":
+		if app.viewManager.IsRejectionConfirmed() {
+			return app, kh.commands.RejectDeployment(app.ctx, app.viewManager)
+		}
+		app.viewManager.HideRejectionConfirm()
+		return app, nil
+	case "esc":
+		app.viewManager.HideRejectionConfirm()
+		return app, nil
+	case "y", "Y":
+		return app, kh.commands.RejectDeployment(app.ctx, app.viewManager)
+	case "n", "N":
+		app.viewManager.HideRejectionConfirm()
+		return app, nil
+	default:
+		return app, nil
+	}
+}
+
+func (kh *DefaultKeyHandler) handleBulkConfirmKeys(msg tea.KeyMsg, app *BubbleApp) (tea.Model, tea.Cmd) {
+	if app.viewManager.IsEditingComment() {
+		return kh.handleCommentInputKeys(msg, app, app.viewManager.HideBulkConfirm)
+	}
+	
+	switch msg.String() {
+	case "tab":
+		if app.viewManager.GetBulkAction() == BulkApprove {
+			app.viewManager.SetEditingComment(true)
+		}
+		return app, nil
+	case "left":
+		app.viewManager.SetBulkSelection(0)
+		return app, nil
+	case "right":
+		app.viewManager.SetBulkSelection(1)
+		return app, nil
+	case "enter":
+		if app.viewManager.IsBulkConfirmed() {
+			return app, kh.runBulkAction(app)
+		}
+		app.viewManager.HideBulkConfirm()
+		return app, nil
+	case "esc":
+		app.viewManager.HideBulkConfirm()
+		return app, nil
+	case "y", "Y":
+		return app, kh.runBulkAction(app)
+	case "n", "N":
+		app.viewManager.HideBulkConfirm()
+		return app, nil
+	default:
+		return app, nil
+	}
+}
+
+// runBulkAction starts the confirmed bulk action on its target runs
+func (kh *DefaultKeyHandler) runBulkAction(app *BubbleApp) tea.Cmd {
+	jobs := app.viewManager.GetBulkTargetJobs()
+	if app.viewManager.GetBulkAction() == BulkApprove {
+		return kh.commands.BulkApprove(app.ctx, jobs, app.viewManager.GetComment())
+	}
+	return kh.commands.BulkCancel(app.ctx, jobs)
+}
+
+// handleCommentInputKeys handles keys while the review comment input has focus.
+// Enter or Tab moves on to the Yes/No buttons, Esc closes the popup via hide.
+func (kh *DefaultKeyHandler) handleCommentInputKeys(msg tea.KeyMsg, app *BubbleApp, hide func()) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "tab":
+		app.viewManager.SetEditingComment(false)
+		return app, nil
+	case "esc":
+		hide()
+		return app, nil
+	default:
+		return app, app.viewManager.UpdateCommentInput(msg)
+	}
+}
+
 func (kh *DefaultKeyHandler) handleCancelConfirmKeys(msg tea.KeyMsg, app *BubbleApp) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "left":
@@ -102,7 +232,138 @@ func (kh *DefaultKeyHandler) handleHelpKeys(msg tea.KeyMsg, app *BubbleApp) (tea
 	}
 }
 
+func (kh *DefaultKeyHandler) handleJobDetailKeys(msg tea.KeyMsg, app *BubbleApp) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		app.cancel()
+		return app, tea.Quit
+	case "esc", "enter", "backspace":
+		app.viewManager.HideJobDetail()
+		return app, nil
+	case "up", "k":
+		app.viewManager.MoveDetailCursor(-1)
+		return app, nil
+	case "down", "j":
+		app.viewManager.MoveDetailCursor(1)
+		return app, nil
+	case "r":
+		return app.refreshJobDetail()
+	case "o":
+		return app, kh.commands.OpenWorkflowJob(app.viewManager)
+	case "l":
+		return app.showJobLogs()
+	default:
+		return app, nil
+	}
+}
+
+func (kh *DefaultKeyHandler) handleLogViewerKeys(msg tea.KeyMsg, app *BubbleApp, viewer *LogViewer) (tea.Model, tea.Cmd) {
+	if viewer.IsSearching() {
+		switch msg.String() {
+		case "enter":
+			viewer.SubmitSearch()
+			return app, nil
+		case "esc":
+			viewer.CancelSearch()
+			return app, nil
+		default:
+			return app, viewer.UpdateSearch(msg)
+		}
+	}
+	
+	switch msg.String() {
+	case "ctrl+c", "q":
+		app.cancel()
+		return app, tea.Quit
+	case "esc", "backspace":
+		app.viewManager.CloseLogViewer()
+		return app, nil
+	case "r":
+		return app.reloadJobLogs()
+	case "/":
+		viewer.StartSearch()
+		return app, nil
+	case "n":
+		viewer.NextMatch(1)
+		return app, nil
+	case "N":
+		viewer.NextMatch(-1)
+		return app, nil
+	case "tab":
+		viewer.SelectGroup(1)
+		return app, nil
+	case "shift+tab":
+		viewer.SelectGroup(-1)
+		return app, nil
+	case " ", "enter":
+		viewer.ToggleGroup()
+		return app, nil
+	case "z":
+		viewer.SetAllFolded(true)
+		return app, nil
+	case "Z":
+		viewer.SetAllFolded(false)
+		return app, nil
+	case "t":
+		viewer.ToggleTimestamps()
+		return app, nil
+	case "g", "home":
+		viewer.GotoTop()
+		return app, nil
+	case "G", "end":
+		viewer.GotoBottom()
+		return app, nil
+	default:
+		// Arrow keys, j/k, pgup/pgdown and friends scroll the viewport
+		return app, viewer.Update(msg)
+	}
+}
+
+// handleFilterInputKeys handles keys while the job table filter is being typed
+func (kh *DefaultKeyHandler) handleFilterInputKeys(msg tea.KeyMsg, app *BubbleApp) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		app.cancel()
+		return app, tea.Quit
+	case "enter", "tab":
+		app.viewManager.StopFilterEditing()
+		return app, nil
+	case "esc":
+		app.viewManager.ClearFilter()
+		return app, nil
+	default:
+		return app, app.viewManager.UpdateFilterInput(msg)
+	}
+}
+
+// handleCommandInputKeys handles keys while a : command is being typed
+func (kh *DefaultKeyHandler) handleCommandInputKeys(msg tea.KeyMsg, app *BubbleApp) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		app.cancel()
+		return app, tea.Quit
+	case "enter":
+		line := app.viewManager.GetCommandInput()
+		app.viewManager.StopCommandInput()
+		return app.executeCommand(line)
+	case "esc":
+		app.viewManager.StopCommandInput()
+		return app, nil
+	case "tab":
+		return app.completeCommandInput()
+	default:
+		return app, app.viewManager.UpdateCommandInput(msg)
+	}
+}
+
 func (kh *DefaultKeyHandler) handleMainViewKeys(msg tea.KeyMsg, app *BubbleApp) (tea.Model, tea.Cmd) {
+	if app.viewManager.IsEditingFilter() {
+		return kh.handleFilterInputKeys(msg, app)
+	}
+	if app.viewManager.IsEditingCommand() {
+		return kh.handleCommandInputKeys(msg, app)
+	}
+	
 	switch msg.String() {
 	case "ctrl+c", "q":
 		app.cancel()
@@ -112,7 +373,31 @@ func (kh *DefaultKeyHandler) handleMainViewKeys(msg tea.KeyMsg, app *BubbleApp)
 		app.showHelp = !app.showHelp
 		return app, nil
 		
+	case "/":
+		app.viewManager.StartFilterEditing()
+		return app, nil
+		
+	case ":":
+		app.viewManager.StartCommandInput()
+		return app, nil
+		
 	case "esc":
+		// Dismiss bulk results first, then the selection, then the filter
+		switch {
+		case len(app.bulkResults) > 0:
+			app.bulkResults = nil
+		case len(app.viewManager.GetSelectedJobs()) > 0:
+			app.viewManager.ClearSelection()
+		default:
+			app.viewManager.ClearFilter()
+		}
+		return app, nil
+		
+	case " ":
+		return app.toggleSelection()
+		
+	case "ctrl+a":
+		app.viewManager.ToggleAllSelection(app.getJobsForCurrentView())
 		return app, nil
 		
 	case "t":
@@ -123,13 +408,43 @@ func (kh *DefaultKeyHandler) handleMainViewKeys(msg tea.KeyMsg, app *BubbleApp)
 		
 	case "a":
 		if app.viewManager.GetCurrentView() == ViewPending {
+			if len(app.viewManager.GetSelectedJobs()) > 0 {
+				return app.showBulkConfirmation(BulkApprove)
+			}
 			return app.showApprovalConfirmation()
 		}
 		return app, nil
 		
+	case "x":
+		if app.viewManager.GetCurrentView() == ViewPending {
+			return app.showRejectionConfirmation()
+		}
+		return app, nil
+		
+	case "m":
+		if app.viewManager.GetCurrentView() == ViewPending {
+			app.viewManager.ToggleApprovableOnly()
+		}
+		return app, nil
+		
 	case "c":
+		if len(app.viewManager.GetSelectedJobs()) > 0 {
+			return app.showBulkConfirmation(BulkCancel)
+		}
 		return app.showCancelConfirmation()
 		
+	case "s":
+		return app.keepSelection(func() (tea.Model, tea.Cmd) {
+			app.viewManager.CycleSortKey()
+			return app, nil
+		})
+		
+	case "S":
+		return app.keepSelection(func() (tea.Model, tea.Cmd) {
+			app.viewManager.ToggleSortDirection()
+			return app, nil
+		})
+		
 	case "up", "k":
 		return app.moveCursorUp()
 		
@@ -137,10 +452,10 @@ func (kh *DefaultKeyHandler) handleMainViewKeys(msg tea.KeyMsg, app *BubbleApp)
 		return app.moveCursorDown()
 		
 	case "enter":
-		return app, nil
+		return app.showJobDetail()
 		
 	case "o":
-		return app, kh.commands.JumpToActions(app.viewManager, app.jobs, app.recentJobs)
+		return app, kh.commands.JumpToActions(app.viewManager, app.getJobsForCurrentView())
 		
 	case "left":
 		return app.navigatePageLeft()
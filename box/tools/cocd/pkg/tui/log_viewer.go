@@ -0,0 +1,384 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// logTimestampPattern matches the timestamp GitHub prefixes to every log line
+var logTimestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z `)
+
+// logLine is a single line of a workflow job log
+type logLine struct {
+	timestamp string
+	text      string
+	group     int // Index of the enclosing step group, -1 if none
+}
+
+// logGroup is a foldable ##[group] ... ##[endgroup] section
+type logGroup struct {
+	title  string
+	header int // Index of the header line
+	size   int // Number of lines inside the group
+}
+
+// LogViewer is a scrollable, searchable view of a workflow job log with foldable step groups
+type LogViewer struct {
+	Title string
+
+	lines  []logLine
+	groups []logGroup
+	folded []bool
+
+	loading bool
+	err     string
+
+	viewport       viewport.Model
+	visibleToLine  []int // Maps viewport rows to indexes in lines
+	selectedGroup  int
+	showTimestamps bool
+
+	searchInput textinput.Model
+	searching   bool
+	query       string
+	matches     []int // Viewport rows containing the query
+	matchIndex  int
+}
+
+// NewLogViewer creates an empty log viewer that shows a loading state
+func NewLogViewer(title string, width, height int) *LogViewer {
+	searchInput := textinput.New()
+	searchInput.Prompt = "/"
+	searchInput.Placeholder = "search"
+	searchInput.CharLimit = 200
+	searchInput.Cursor.SetMode(cursor.CursorStatic)
+
+	lv := &LogViewer{
+		Title:         title,
+		loading:       true,
+		viewport:      viewport.New(width, height),
+		selectedGroup: -1,
+		searchInput:   searchInput,
+	}
+	return lv
+}
+
+// SetContent parses raw log text and folds every step group
+func (lv *LogViewer) SetContent(raw string) {
+	lv.lines = nil
+	lv.groups = nil
+	lv.loading = false
+	lv.err = ""
+
+	currentGroup := -1
+	for _, text := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		timestamp := logTimestampPattern.FindString(text)
+		text = strings.TrimPrefix(text, timestamp)
+		marker := ansi.Strip(text)
+
+		switch {
+		case strings.HasPrefix(marker, "##[group]"):
+			lv.groups = append(lv.groups, logGroup{
+				title:  strings.TrimPrefix(marker, "##[group]"),
+				header: len(lv.lines),
+			})
+			currentGroup = len(lv.groups) - 1
+			lv.lines = append(lv.lines, logLine{timestamp: timestamp, text: text, group: currentGroup})
+			continue
+		case strings.HasPrefix(marker, "##[endgroup]"):
+			currentGroup = -1
+			continue
+		}
+
+		if currentGroup >= 0 {
+			lv.groups[currentGroup].size++
+		}
+		lv.lines = append(lv.lines, logLine{timestamp: timestamp, text: text, group: currentGroup})
+	}
+
+	lv.folded = make([]bool, len(lv.groups))
+	for i := range lv.folded {
+		lv.folded[i] = true
+	}
+	lv.selectedGroup = -1
+	lv.refresh()
+	lv.viewport.GotoBottom()
+}
+
+// SetError replaces the log with an error message
+func (lv *LogViewer) SetError(err string) {
+	lv.loading = false
+	lv.err = err
+}
+
+// IsLoading returns whether the log is still being downloaded
+func (lv *LogViewer) IsLoading() bool {
+	return lv.loading
+}
+
+// Error returns the download error, if any
+func (lv *LogViewer) Error() string {
+	return lv.err
+}
+
+// SetSize resizes the viewport
+func (lv *LogViewer) SetSize(width, height int) {
+	lv.viewport.Width = width
+	lv.viewport.Height = height
+	lv.refresh()
+}
+
+// Update forwards scroll keys to the viewport
+func (lv *LogViewer) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	lv.viewport, cmd = lv.viewport.Update(msg)
+	return cmd
+}
+
+// View renders the visible part of the log
+func (lv *LogViewer) View() string {
+	return lv.viewport.View()
+}
+
+// ScrollPercent returns how far the viewport is scrolled
+func (lv *LogViewer) ScrollPercent() float64 {
+	return lv.viewport.ScrollPercent()
+}
+
+// GotoTop scrolls to the first line
+func (lv *LogViewer) GotoTop() {
+	lv.viewport.GotoTop()
+}
+
+// GotoBottom scrolls to the last line
+func (lv *LogViewer) GotoBottom() {
+	lv.viewport.GotoBottom()
+}
+
+// ToggleTimestamps shows or hides the line timestamps
+func (lv *LogViewer) ToggleTimestamps() {
+	lv.showTimestamps = !lv.showTimestamps
+	lv.refresh()
+}
+
+// SelectGroup moves the group selection forward or backward and scrolls to it
+func (lv *LogViewer) SelectGroup(direction int) {
+	if len(lv.groups) == 0 {
+		return
+	}
+
+	lv.selectedGroup += direction
+	if lv.selectedGroup >= len(lv.groups) {
+		lv.selectedGroup = 0
+	}
+	if lv.selectedGroup < 0 {
+		lv.selectedGroup = len(lv.groups) - 1
+	}
+
+	lv.refresh()
+	lv.scrollToLine(lv.groups[lv.selectedGroup].header)
+}
+
+// ToggleGroup folds or unfolds the selected group
+func (lv *LogViewer) ToggleGroup() {
+	if lv.selectedGroup < 0 || lv.selectedGroup >= len(lv.groups) {
+		return
+	}
+	lv.folded[lv.selectedGroup] = !lv.folded[lv.selectedGroup]
+	lv.refresh()
+	lv.scrollToLine(lv.groups[lv.selectedGroup].header)
+}
+
+// SetAllFolded folds or unfolds every group
+func (lv *LogViewer) SetAllFolded(folded bool) {
+	for i := range lv.folded {
+		lv.folded[i] = folded
+	}
+	lv.refresh()
+}
+
+// IsSearching returns whether the search input has focus
+func (lv *LogViewer) IsSearching() bool {
+	return lv.searching
+}
+
+// StartSearch focuses the search input
+func (lv *LogViewer) StartSearch() {
+	lv.searching = true
+	lv.searchInput.SetValue(lv.query)
+	lv.searchInput.CursorEnd()
+	lv.searchInput.Focus()
+}
+
+// CancelSearch leaves the search input without changing the current query
+func (lv *LogViewer) CancelSearch() {
+	lv.searching = false
+	lv.searchInput.Blur()
+}
+
+// UpdateSearch forwards a key message to the search input
+func (lv *LogViewer) UpdateSearch(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	lv.searchInput, cmd = lv.searchInput.Update(msg)
+	return cmd
+}
+
+// SubmitSearch applies the typed query, unfolding groups that contain matches
+func (lv *LogViewer) SubmitSearch() {
+	lv.CancelSearch()
+	lv.query = strings.TrimSpace(lv.searchInput.Value())
+
+	if lv.query != "" {
+		needle := strings.ToLower(lv.query)
+		for _, line := range lv.lines {
+			if line.group >= 0 && strings.Contains(strings.ToLower(ansi.Strip(line.text)), needle) {
+				lv.folded[line.group] = false
+			}
+		}
+	}
+
+	lv.refresh()
+	lv.matchIndex = -1
+	lv.NextMatch(1)
+}
+
+// NextMatch scrolls to the next (1) or previous (-1) search match
+func (lv *LogViewer) NextMatch(direction int) {
+	if len(lv.matches) == 0 {
+		return
+	}
+
+	lv.matchIndex += direction
+	if lv.matchIndex >= len(lv.matches) {
+		lv.matchIndex = 0
+	}
+	if lv.matchIndex < 0 {
+		lv.matchIndex = len(lv.matches) - 1
+	}
+	lv.viewport.SetYOffset(lv.matches[lv.matchIndex])
+}
+
+// SearchView renders the search input or a summary of the active search
+func (lv *LogViewer) SearchView() string {
+	if lv.searching {
+		return lv.searchInput.View()
+	}
+	if lv.query == "" {
+		return ""
+	}
+	if len(lv.matches) == 0 {
+		return fmt.Sprintf("/%s (no matches)", lv.query)
+	}
+	return fmt.Sprintf("/%s (%d/%d)", lv.query, lv.matchIndex+1, len(lv.matches))
+}
+
+// scrollToLine scrolls the viewport so that the given log line is at the top
+func (lv *LogViewer) scrollToLine(index int) {
+	for row, line := range lv.visibleToLine {
+		if line == index {
+			lv.viewport.SetYOffset(row)
+			return
+		}
+	}
+}
+
+// refresh rebuilds the viewport content from the fold, timestamp and search state
+func (lv *LogViewer) refresh() {
+	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("4")).Bold(true)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	timestampStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	matchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("11"))
+
+	needle := strings.ToLower(lv.query)
+
+	var rows []string
+	lv.visibleToLine = lv.visibleToLine[:0]
+	lv.matches = lv.matches[:0]
+
+	for i, line := range lv.lines {
+		isHeader := line.group >= 0 && lv.groups[line.group].header == i
+		if !isHeader && line.group >= 0 && lv.folded[line.group] {
+			continue
+		}
+
+		text := line.text
+		plain := ansi.Strip(text)
+
+		switch {
+		case isHeader:
+			group := lv.groups[line.group]
+			icon := "▾"
+			if lv.folded[line.group] {
+				icon = "▸"
+			}
+			text = fmt.Sprintf("%s %s (%d lines)", icon, group.title, group.size)
+			plain = text
+			if line.group == lv.selectedGroup {
+				text = selectedStyle.Render(text)
+			} else {
+				text = headerStyle.Render(text)
+			}
+		case strings.HasPrefix(plain, "##[error]"):
+			plain = "Error: " + strings.TrimPrefix(plain, "##[error]")
+			text = errorStyle.Render(plain)
+		case strings.HasPrefix(plain, "##[warning]"):
+			plain = "Warning: " + strings.TrimPrefix(plain, "##[warning]")
+			text = warningStyle.Render(plain)
+		}
+
+		if needle != "" && strings.Contains(strings.ToLower(plain), needle) {
+			lv.matches = append(lv.matches, len(rows))
+			text = highlightMatches(plain, lv.query, matchStyle)
+		}
+
+		if lv.showTimestamps && line.timestamp != "" {
+			text = timestampStyle.Render(strings.TrimSpace(line.timestamp)) + " " + text
+		}
+
+		rows = append(rows, text)
+		lv.visibleToLine = append(lv.visibleToLine, i)
+	}
+
+	lv.viewport.SetContent(strings.Join(rows, "\n"))
+}
+
+// highlightMatches renders every case-insensitive occurrence of query in text with style.
+// The text must not contain ANSI sequences. Matching compares rune by rune on the
+// text itself, since lowercasing can change the byte length of non-ASCII or invalid UTF-8 text.
+func highlightMatches(text, query string, style lipgloss.Style) string {
+	queryRunes := utf8.RuneCountInString(query)
+	if queryRunes == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	written := 0
+	for i := 0; i < len(text); {
+		end := i
+		for n := 0; n < queryRunes && end < len(text); n++ {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		}
+		if strings.EqualFold(text[i:end], query) {
+			b.WriteString(text[written:i])
+			b.WriteString(style.Render(text[i:end]))
+			written, i = end, end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	b.WriteString(text[written:])
+	return b.String()
+}
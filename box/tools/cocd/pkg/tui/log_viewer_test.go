@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestHighlightMatches(t *testing.T) {
+	mark := lipgloss.NewStyle().Transform(func(s string) string { return "[" + s + "]" })
+
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{"no match", "build succeeded", "error", "build succeeded"},
+		{"empty query", "build failed", "", "build failed"},
+		{"ignores case", "Error: ERROR error", "error", "[Error]: [ERROR] [error]"},
+		{"non-ASCII before match", "İİİ error", "error", "İİİ [error]"},
+		{"non-ASCII query", "Größe GRÖSSE größe", "größe", "[Größe] GRÖSSE [größe]"},
+		{"invalid UTF-8 before match", "\xff\xff\xff\xff error", "error", "\xff\xff\xff\xff [error]"},
+		{"invalid UTF-8 after match", "error\xff\xfe", "ERROR", "[error]\xff\xfe"},
+		{"match at the end", "step failed", "failed", "step [failed]"},
+		{"query longer than text", "err", "error", "err"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightMatches(tt.text, tt.query, mark); got != tt.want {
+				t.Errorf("highlightMatches(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package tui
 import (
 	"time"
 	
+	"github.com/google/go-github/v60/github"
+	githubclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/history"
 	"github.com/younsl/cocd/pkg/monitor"
 	"github.com/younsl/cocd/pkg/scanner"
 )
@@ -20,9 +23,46 @@ type (
 	cancelProcessingMsg   struct{ job *scanner.JobStatus }
 	approvalSuccessMsg    struct{}
 	approvalProcessingMsg struct{ job *scanner.JobStatus }
+	rejectionSuccessMsg   struct{}
+	organizationSwitchedMsg string
+	bulkResultMsg         struct {
+		action  string
+		results []BulkResult
+	}
+	workflowJobsMsg       struct {
+		runID int64
+		jobs  []*github.WorkflowJob
+	}
+	jobLogsMsg            struct {
+		jobID   int64
+		content string
+	}
+	pendingDeploymentsMsg struct {
+		runID       int64
+		deployments []*githubclient.PendingDeployment
+	}
 	recentJobUpdateMsg      monitor.JobUpdate
 	jobUpdateMsg            monitor.JobUpdate
 	startRecentStreamingMsg struct{}
 	delayedRefreshMsg     struct{}
+	historyMsg            struct {
+		org  string
+		runs []history.Run
+	}
 )
 
+// Bulk actions applied to the selected runs
+const (
+	BulkApprove = "approve"
+	BulkCancel  = "cancel"
+)
+
+// bulkConcurrency limits the API calls a bulk action runs at once
+const bulkConcurrency = 5
+
+// BulkResult is the outcome of a bulk action for a single run
+type BulkResult struct {
+	Job    scanner.JobStatus
+	Detail string // What was done, e.g. the approved environments
+	Err    error
+}
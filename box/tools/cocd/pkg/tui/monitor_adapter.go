@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 	
+	githubclient "github.com/younsl/cocd/pkg/github"
 	"github.com/younsl/cocd/pkg/monitor"
 	"github.com/younsl/cocd/pkg/scanner"
 )
@@ -68,6 +69,21 @@ func (ma *MonitorAdapter) GetAuthenticatedUser(ctx context.Context) (string, err
 	return ma.monitor.GetAuthenticatedUser(ctx)
 }
 
+// GetCachedRepositoryNames returns the repository names from the monitor's repository cache
+func (ma *MonitorAdapter) GetCachedRepositoryNames() []string {
+	return ma.monitor.GetCachedRepositoryNames()
+}
+
+// SwitchOrganization makes the monitor watch another organization
+func (ma *MonitorAdapter) SwitchOrganization(ctx context.Context, org string) error {
+	return ma.monitor.SwitchOrganization(ctx, org)
+}
+
+// GetAPIStats returns the API call statistics and rate limit of the monitor's client
+func (ma *MonitorAdapter) GetAPIStats() githubclient.APIStats {
+	return ma.monitor.GetClient().Stats()
+}
+
 // progressTrackerAdapter adapts monitor.ProgressTracker to ProgressTracker interface
 type progressTrackerAdapter struct {
 	tracker *monitor.ProgressTracker
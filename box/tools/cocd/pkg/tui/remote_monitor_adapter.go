@@ -0,0 +1,268 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	githubclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/monitor"
+	"github.com/younsl/cocd/pkg/remote"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+const (
+	// remoteProgressInterval is how often the scan progress is fetched from the server
+	remoteProgressInterval = 2 * time.Second
+	// remoteReconnectDelay is the wait before the event stream is reopened
+	remoteReconnectDelay = 5 * time.Second
+	// remoteEventSettle groups the events of one server scan into a single refresh
+	remoteEventSettle = 500 * time.Millisecond
+)
+
+// RemoteMonitorAdapter adapts a cocd server to the Monitor interface, so that
+// the TUI shows the scans of a shared server instead of scanning GitHub itself
+type RemoteMonitorAdapter struct {
+	client *remote.Client
+	// Countdowns of the TUI's own refresh timers
+	tracker *monitor.ProgressTracker
+
+	mu                sync.Mutex
+	progress          monitor.ScanProgress
+	progressFetchedAt time.Time
+	progressInFlight  bool
+	user              string
+	repositories      map[string]struct{}
+}
+
+// NewRemoteMonitorAdapter creates a monitor backed by a cocd server
+func NewRemoteMonitorAdapter(client *remote.Client) Monitor {
+	return &RemoteMonitorAdapter{
+		client:       client,
+		tracker:      monitor.NewProgressTracker(),
+		progress:     monitor.ScanProgress{ScanMode: monitor.ScanModeIdle},
+		repositories: make(map[string]struct{}),
+	}
+}
+
+// StartMonitoring sends the pending jobs every time the server reports run state changes
+func (rma *RemoteMonitorAdapter) StartMonitoring(ctx context.Context, jobsChan chan []scanner.JobStatus) {
+	changed := make(chan struct{}, 1)
+	go func() {
+		for ctx.Err() == nil {
+			rma.client.Events(ctx, func(monitor.Event) {
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			})
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(remoteReconnectDelay):
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(remoteEventSettle):
+		}
+
+		jobs, err := rma.GetPendingJobs(ctx)
+		if err != nil {
+			continue
+		}
+
+		select {
+		case jobsChan <- jobs:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// GetPendingJobs returns the pending jobs of the server's last scan
+func (rma *RemoteMonitorAdapter) GetPendingJobs(ctx context.Context) ([]scanner.JobStatus, error) {
+	jobs, err := rma.client.PendingJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	forgetReviewers(jobs)
+	rma.rememberRepositories(jobs)
+	return jobs, nil
+}
+
+// GetRecentJobs returns the recent jobs of the server's last scan
+func (rma *RemoteMonitorAdapter) GetRecentJobs(ctx context.Context) ([]scanner.JobStatus, error) {
+	jobs, err := rma.client.RecentJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	forgetReviewers(jobs)
+	rma.rememberRepositories(jobs)
+	return jobs, nil
+}
+
+// GetClient returns a GitHub client whose requests the server forwards with the user's token
+func (rma *RemoteMonitorAdapter) GetClient() interface{} {
+	return rma.client.GitHub()
+}
+
+// GetProgressTracker returns the tracker of the TUI's refresh countdowns
+func (rma *RemoteMonitorAdapter) GetProgressTracker() ProgressTracker {
+	return &progressTrackerAdapter{
+		tracker: rma.tracker,
+	}
+}
+
+// GetScanProgress returns the progress of the server's scan with the local
+// refresh countdown. It is called on every render, so the server's progress is
+// refreshed in the background and the last known one returned right away.
+func (rma *RemoteMonitorAdapter) GetScanProgress() monitor.ScanProgress {
+	rma.mu.Lock()
+	progress := rma.progress
+	if !rma.progressInFlight && time.Since(rma.progressFetchedAt) > remoteProgressInterval {
+		rma.progressInFlight = true
+		go rma.refreshProgress()
+	}
+	rma.mu.Unlock()
+
+	local := rma.tracker.GetProgress()
+	progress.NextScanAt = local.NextScanAt
+	progress.ScanCountdown = local.ScanCountdown
+	progress.ScanCycleCount = local.ScanCycleCount
+	progress.IsNextScanFull = local.IsNextScanFull
+	return progress
+}
+
+func (rma *RemoteMonitorAdapter) refreshProgress() {
+	response, err := rma.client.Progress(context.Background())
+
+	rma.mu.Lock()
+	defer rma.mu.Unlock()
+
+	rma.progressInFlight = false
+	rma.progressFetchedAt = time.Now()
+	if err == nil {
+		rma.progress = response.Progress
+	}
+}
+
+// GetUpdateInterval returns the scan interval of the server
+func (rma *RemoteMonitorAdapter) GetUpdateInterval() int {
+	return rma.client.Info().ScanInterval
+}
+
+// GetRecentJobsWithStreaming sends the recent jobs of the server's last scan
+// one repository at a time, the way a local scan reports them
+func (rma *RemoteMonitorAdapter) GetRecentJobsWithStreaming(ctx context.Context, jobUpdateChan chan<- monitor.JobUpdate) error {
+	jobs, err := rma.GetRecentJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var repositories []string
+	byRepository := make(map[string][]scanner.JobStatus)
+	for _, job := range jobs {
+		if _, ok := byRepository[job.Repository]; !ok {
+			repositories = append(repositories, job.Repository)
+		}
+		byRepository[job.Repository] = append(byRepository[job.Repository], job)
+	}
+
+	progress := rma.GetScanProgress()
+	for _, repository := range repositories {
+		select {
+		case jobUpdateChan <- monitor.JobUpdate{Jobs: byRepository[repository], CompletedRepo: repository, Progress: progress}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	progress.ScanMode = "Completed"
+	select {
+	case jobUpdateChan <- monitor.JobUpdate{Progress: progress}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// GetAuthenticatedUser returns the login of the user's token. It is asked for
+// on every render, so the answer is kept instead of going through the server each time.
+func (rma *RemoteMonitorAdapter) GetAuthenticatedUser(ctx context.Context) (string, error) {
+	rma.mu.Lock()
+	user := rma.user
+	rma.mu.Unlock()
+	if user != "" {
+		return user, nil
+	}
+
+	githubUser, _, err := rma.client.GitHub().GetAuthenticatedUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	rma.mu.Lock()
+	rma.user = githubUser.GetLogin()
+	rma.mu.Unlock()
+	return githubUser.GetLogin(), nil
+}
+
+// GetCachedRepositoryNames returns the repositories of the jobs received from the server
+func (rma *RemoteMonitorAdapter) GetCachedRepositoryNames() []string {
+	rma.mu.Lock()
+	defer rma.mu.Unlock()
+
+	names := make([]string, 0, len(rma.repositories))
+	for name := range rma.repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SwitchOrganization is not supported, the server decides which organization is scanned
+func (rma *RemoteMonitorAdapter) SwitchOrganization(ctx context.Context, org string) error {
+	return fmt.Errorf("connected to a cocd server that monitors %s, run cocd without --server to switch organizations", rma.client.Info().Organization)
+}
+
+// GetAPIStats returns the rate limit of the user's token, as seen through the server
+func (rma *RemoteMonitorAdapter) GetAPIStats() githubclient.APIStats {
+	return rma.client.GitHub().Stats()
+}
+
+// forgetReviewers drops whether runs can be approved, which the server's scan
+// answered for its own token rather than the user's. The approval popup then
+// asks GitHub through the server with the user's token.
+func forgetReviewers(jobs []scanner.JobStatus) {
+	for i := range jobs {
+		jobs[i].ReviewChecked = false
+		jobs[i].CanApprove = false
+		jobs[i].Reviewers = nil
+	}
+}
+
+func (rma *RemoteMonitorAdapter) rememberRepositories(jobs []scanner.JobStatus) {
+	rma.mu.Lock()
+	defer rma.mu.Unlock()
+
+	for _, job := range jobs {
+		rma.repositories[job.Repository] = struct{}{}
+	}
+}
+
+// Ensure RemoteMonitorAdapter implements Monitor interface
+var _ Monitor = (*RemoteMonitorAdapter)(nil)
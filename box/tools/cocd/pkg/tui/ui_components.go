@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/google/go-github/v60/github"
 	"github.com/mattn/go-runewidth"
+	githubclient "github.com/younsl/cocd/pkg/github"
 	"github.com/younsl/cocd/pkg/monitor"
 	"github.com/younsl/cocd/pkg/scanner"
 )
@@ -62,13 +64,14 @@ func (ui *UIComponents) RenderHeader(monitor Monitor) string {
 	organization := fmt.Sprintf("Org: %s", org)
 	
 	status := ui.getConnectionStatus(false, "")
+	rateLimit := ui.getRateLimitInfo(monitor.GetAPIStats())
 	
 	scanInfo := ui.getScanInfo(progress)
 	timerInfo := ui.getTimerInfo(progress)
 	keyBindings := ui.getKeyBindings()
 	
-	return fmt.Sprintf("%s  %s  %s  %s  %s  Status: %s\n%s\n%s\n%s", 
-		title, memory, server, organization, userInfo, status, scanInfo, timerInfo, keyBindings)
+	return fmt.Sprintf("%s  %s  %s  %s  %s  Status: %s%s\n%s\n%s\n%s", 
+		title, memory, server, organization, userInfo, status, rateLimit, scanInfo, timerInfo, keyBindings)
 }
 
 // RenderViewSelector renders the view selector
@@ -85,7 +88,55 @@ func (ui *UIComponents) RenderViewSelector(currentView ViewType, pendingCount, r
 	pending := pendingStyle.Render(fmt.Sprintf("Approval Waiting Jobs [%d]", pendingCount))
 	recent := recentStyle.Render(fmt.Sprintf("Recent Jobs [%d]", recentCount))
 	
-	return fmt.Sprintf("%s  %s", pending, recent)
+	selector := fmt.Sprintf("%s  %s", pending, recent)
+	if vm.IsApprovableOnly() {
+		selector += lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("  (approvable by me only)")
+	}
+	if repo := vm.GetRepositoryScope(); repo != "" {
+		selector += lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(fmt.Sprintf("  (repo: %s)", repo))
+	}
+	
+	return selector
+}
+
+// RenderFilterBar renders the job table filter while it is typed or active
+func (ui *UIComponents) RenderFilterBar(vm ViewManagerInterface) string {
+	if vm.IsEditingFilter() {
+		bar := vm.GetFilterInputView()
+		if errMsg := vm.GetFilterError(); errMsg != "" {
+			bar += "  " + lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(errMsg)
+		}
+		return bar
+	}
+	
+	if filter := vm.GetFilter(); filter != nil {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("Filter: "+filter.String()) +
+			lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("  [/] edit [esc] clear")
+	}
+	
+	return ""
+}
+
+// RenderCommandBar renders the : command prompt and its completion candidates
+func (ui *UIComponents) RenderCommandBar(vm ViewManagerInterface) string {
+	if !vm.IsEditingCommand() {
+		return ""
+	}
+	
+	bar := vm.GetCommandInputView()
+	if suggestions := vm.GetCommandSuggestions(); len(suggestions) > 0 {
+		const maxSuggestions = 10
+		shown := suggestions
+		if len(shown) > maxSuggestions {
+			shown = shown[:maxSuggestions]
+		}
+		hint := strings.Join(shown, "  ")
+		if len(suggestions) > maxSuggestions {
+			hint += fmt.Sprintf("  (+%d more)", len(suggestions)-maxSuggestions)
+		}
+		bar += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(hint)
+	}
+	return bar
 }
 
 // RenderJobTable renders the job table
@@ -113,7 +164,8 @@ func (ui *UIComponents) RenderJobTable(jobs []scanner.JobStatus, cursor int, vm
 	actorWidth := columnWidths[5]
 	
 	// Always render table header
-	ui.renderTableHeader(&b, repoWidth, jobWidth, idWidth, statusWidth, branchWidth, actorWidth, ageWidth)
+	sortKey, descending := vm.GetSort()
+	ui.renderTableHeader(&b, sortKey, descending, repoWidth, jobWidth, idWidth, statusWidth, branchWidth, actorWidth, ageWidth)
 	
 	if len(jobs) == 0 {
 		// Show "No jobs found" message after header
@@ -178,8 +230,8 @@ func (ui *UIComponents) RenderPagination(currentView ViewType, vm ViewManagerInt
 	columnWidths := ui.calculateColumnWidths(jobs)
 	ageWidth := ui.calculateAgeColumnWidth(jobs)
 	
-	// Calculate total width up to AGE column (all columns + spaces between them)
-	totalWidthUpToAge := 0
+	// Calculate total width up to AGE column (marker + all columns + spaces between them)
+	totalWidthUpToAge := markerWidth + 1
 	for i := 0; i < len(columnWidths); i++ {
 		totalWidthUpToAge += columnWidths[i]
 		if i < len(columnWidths)-1 {
@@ -216,13 +268,27 @@ KEY BINDINGS:
   q, Ctrl+C    Quit
   t            Toggle between Approval Waiting Jobs and Recent Jobs
   r            Refresh current view
-  a            Approve selected deployment (with confirmation)
+  a            Approve selected deployment (pick environments, with confirmation)
+  x            Reject selected deployment (with confirmation)
   c            Cancel selected workflow (with confirmation)
+  Space        Select/unselect run for bulk approve or cancel
+  Ctrl+A       Select/unselect all visible runs
+  m            Show only runs I can approve (Approval Waiting Jobs only)
+  s / S        Cycle sort column / flip sort direction
+  /            Filter jobs (text, /regex/, field:value; Esc clears)
+  :            Command prompt (see COMMANDS)
   h, ?         Toggle this help
   ↑/↓, k/j     Navigate jobs (k=up, j=down)
+  Enter        Show jobs and steps of the selected run (l for job logs)
   ←/→          Navigate pages (Recent Jobs only)
   o            Open GitHub Actions page in browser
 
+MARKERS:
+  *            Run is selected; a and c act on all selected runs
+  !            You are not a required reviewer for this waiting run
+
+COMMANDS:
+%s
 SCAN SETTINGS:
 SETTING              SMART SCAN             RECENT JOBS
 Interval             %-22s Manual only
@@ -233,14 +299,16 @@ API Filter           status="waiting"       All runs
 Cache                Repo/Env (60m/5m)      Repo list (60m)
 Result Limit         All waiting            Last 200 jobs
 
-Press any key to continue...`, intervalStr)
+Press any key to continue...`, commandHelp(), intervalStr)
 	
 	return helpStyle.Render(help)
 }
 
 
 // RenderApprovalConfirm renders the approval confirmation popup
-func (ui *UIComponents) RenderApprovalConfirm(job scanner.JobStatus, selection int) string {
+func (ui *UIComponents) RenderApprovalConfirm(popup ReviewPopup) string {
+	job := popup.Job
+	
 	// Create a centered popup with a more professional design
 	confirmStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("15")).
@@ -263,82 +331,333 @@ func (ui *UIComponents) RenderApprovalConfirm(job scanner.JobStatus, selection i
 		job.Status,
 	)
 	
+	environments := ui.renderEnvironmentChecklist(popup)
+	
 	warning := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("3")).
 		Align(lipgloss.Center).
-		Render("This will approve the deployment to production!")
+		Render("This will approve the selected deployments!")
 	
-	// Add approval message preview
-	ch := NewCommandHandler(nil, ui.config)
-	approvalMessage := ch.(*CommandHandler).generateApprovalMessage()
-	messagePreview := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("6")).
-		Background(lipgloss.Color("8")).
-		Padding(0, 1).
+	// Add review comment input and message preview
+	commentInput, messagePreview := ui.renderReviewComment(popup)
+	
+	buttons := ui.renderConfirmButtons(popup.Selection)
+	
+	instructions := ui.renderReviewInstructions(popup)
+	
+	content := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n\n%s\n\n%s\n\n%s\n\n%s", title, jobInfo, environments, warning, commentInput, messagePreview, buttons, instructions)
+	
+	return confirmStyle.Render(content)
+}
+
+// RenderRejectionConfirm renders the rejection confirmation popup
+func (ui *UIComponents) RenderRejectionConfirm(popup ReviewPopup) string {
+	job := popup.Job
+	
+	confirmStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("15")).
+		Padding(1, 2).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("1")).
+		Width(60).
+		Align(lipgloss.Center)
+	
+	title := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("1")).
+		Bold(true).
 		Align(lipgloss.Center).
-		Render(fmt.Sprintf("Message: %s", approvalMessage))
+		Render("⚠️  Confirm Deployment Rejection")
 	
-	// Create interactive Yes/No buttons with consistent width
-	buttonWidth := 8
+	jobInfo := fmt.Sprintf(
+		"Repository: %s\nWorkflow: %s\nRun #%d\nStatus: %s",
+		job.Repository,
+		job.WorkflowName,
+		job.RunNumber,
+		job.Status,
+	)
 	
-	// Simple design with color only on selected button
-	var noButton, yesButton string
-	if selection == 0 { // No selected - green background
-		noButton = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("15")).
-			Background(lipgloss.Color("2")).
-			Padding(0, 1).
-			Width(buttonWidth).
-			Align(lipgloss.Center).
-			Border(lipgloss.RoundedBorder()).
-			Bold(true).
-			Render("No")
-		yesButton = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("15")).
-			Padding(0, 1).
-			Width(buttonWidth).
-			Align(lipgloss.Center).
-			Border(lipgloss.RoundedBorder()).
-			Render("Yes")
-	} else { // Yes selected - red background for approval
-		noButton = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("15")).
-			Padding(0, 1).
-			Width(buttonWidth).
-			Align(lipgloss.Center).
-			Border(lipgloss.RoundedBorder()).
-			Render("No")
-		yesButton = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("15")).
-			Background(lipgloss.Color("1")).
-			Padding(0, 1).
-			Width(buttonWidth).
-			Align(lipgloss.Center).
-			Border(lipgloss.RoundedBorder()).
-			Bold(true).
-			Render("Yes")
+	warning := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("3")).
+		Align(lipgloss.Center).
+		Render("This will reject the deployment and fail the run!")
+	
+	// Add review comment input and message preview
+	commentInput, messagePreview := ui.renderReviewComment(popup)
+	
+	buttons := ui.renderConfirmButtons(popup.Selection)
+	
+	instructions := ui.renderReviewInstructions(popup)
+	
+	content := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n\n%s\n\n%s\n\n%s", title, jobInfo, warning, commentInput, messagePreview, buttons, instructions)
+	
+	return confirmStyle.Render(content)
+}
+
+// RenderBulkConfirm renders the confirmation popup for approving or cancelling the selected runs
+func (ui *UIComponents) RenderBulkConfirm(popup BulkPopup) string {
+	color := lipgloss.Color("2")
+	title := fmt.Sprintf("⚠️  Approve %d Deployments", len(popup.Jobs))
+	warning := "Environments you are a required reviewer for will be approved on every run"
+	if popup.Action == BulkCancel {
+		color = lipgloss.Color("11")
+		title = fmt.Sprintf("⚠️  Cancel %d Workflows", len(popup.Jobs))
+		warning = "This action cannot be undone!"
 	}
 	
-	// Create button container with proper spacing
-	buttonContainer := lipgloss.JoinHorizontal(
-		lipgloss.Center,
-		noButton,
-		strings.Repeat(" ", 4), // Space between buttons
-		yesButton,
-	)
+	confirmStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("15")).
+		Padding(1, 2).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(color).
+		Width(70).
+		Align(lipgloss.Center)
 	
-	buttons := lipgloss.NewStyle().
+	titleText := lipgloss.NewStyle().
+		Foreground(color).
+		Bold(true).
 		Align(lipgloss.Center).
-		Render(buttonContainer)
+		Render(title)
+	
+	repositories := make(map[string]bool)
+	for _, job := range popup.Jobs {
+		repositories[job.Repository] = true
+	}
+	summary := fmt.Sprintf("%d runs across %d repositories", len(popup.Jobs), len(repositories))
+	
+	const maxListed = 10
+	lines := []string{}
+	for i, job := range popup.Jobs {
+		if i == maxListed {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(popup.Jobs)-maxListed))
+			break
+		}
+		line := fmt.Sprintf("%s #%d  %s", job.Repository, job.RunNumber, job.WorkflowName)
+		if job.Environment != "" {
+			line += fmt.Sprintf("  (%s)", job.Environment)
+		}
+		lines = append(lines, ui.truncate(line, 64))
+	}
+	targets := lipgloss.NewStyle().Align(lipgloss.Left).Render(strings.Join(lines, "\n"))
+	
+	warningText := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("3")).
+		Italic(true).
+		Align(lipgloss.Center).
+		Render(warning)
+	
+	sections := []string{titleText, summary, targets, warningText}
+	
+	if popup.Action == BulkApprove {
+		review := ReviewPopup{Editing: popup.Editing, Comment: popup.Comment, Message: popup.Message}
+		commentInput, messagePreview := ui.renderReviewComment(review)
+		sections = append(sections, commentInput, messagePreview)
+	}
 	
 	instructions := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("8")).
 		Align(lipgloss.Center).
 		Render("Use ←/→ to select, Enter to confirm, Esc to cancel")
+	if popup.Action == BulkApprove {
+		instructions = ui.renderReviewInstructions(ReviewPopup{Editing: popup.Editing})
+	}
+	sections = append(sections, ui.renderConfirmButtons(popup.Selection), instructions)
 	
-	content := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n\n%s\n\n%s", title, jobInfo, warning, messagePreview, buttons, instructions)
+	return confirmStyle.Render(strings.Join(sections, "\n\n"))
+}
+
+// RenderBulkResults renders the per-run outcome of the last bulk action in the status area
+func (ui *UIComponents) RenderBulkResults(results []BulkResult) string {
+	if len(results) == 0 {
+		return ""
+	}
 	
-	return confirmStyle.Render(content)
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	
+	var b strings.Builder
+	summary := fmt.Sprintf("Bulk action: %d succeeded, %d failed", len(results)-failed, failed)
+	if failed > 0 {
+		b.WriteString(failStyle.Render(summary))
+	} else {
+		b.WriteString(okStyle.Render(summary))
+	}
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("  [esc] dismiss"))
+	b.WriteString("\n")
+	
+	// Failures first, since those need attention
+	const maxShown = 10
+	shown := 0
+	for _, wantFailure := range []bool{true, false} {
+		for _, result := range results {
+			if (result.Err != nil) != wantFailure {
+				continue
+			}
+			if shown == maxShown {
+				break
+			}
+			shown++
+			
+			run := fmt.Sprintf("%s #%d", result.Job.Repository, result.Job.RunNumber)
+			if result.Err != nil {
+				b.WriteString(failStyle.Render(fmt.Sprintf("  ✗ %s: %v", run, result.Err)))
+			} else {
+				b.WriteString(okStyle.Render(fmt.Sprintf("  ✓ %s: %s", run, result.Detail)))
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(results) > shown {
+		b.WriteString(fmt.Sprintf("  ... and %d more\n", len(results)-shown))
+	}
+	
+	return b.String()
+}
+
+// RenderJobDetail renders the detail pane with the jobs and steps of a workflow run
+func (ui *UIComponents) RenderJobDetail(detail JobDetail) string {
+	var b strings.Builder
+	
+	run := detail.Job
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%s #%d  %s", run.Repository, run.RunNumber, run.WorkflowName)))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("Branch: %s  Event: %s  Actor: %s  Status: %s",
+		run.Branch, run.Event, run.Actor, ui.colorStatus(run.Status, run.Status)))
+	b.WriteString("\n")
+	
+	refreshInfo := "Loading..."
+	if !detail.LoadedAt.IsZero() {
+		refreshInfo = fmt.Sprintf("Updated %s ago", ui.formatDuration(time.Since(detail.LoadedAt)))
+		if detail.AutoRefresh {
+			refreshInfo += fmt.Sprintf(" (auto-refresh every %ds)", int(detailRefreshInterval.Seconds()))
+		}
+	}
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(refreshInfo))
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("Keys: [↑↓] select job [l]ogs [r]efresh [o]pen job in browser [esc] back [q]uit"))
+	b.WriteString("\n\n")
+	
+	if detail.WorkflowJobs == nil {
+		if !detail.Loading {
+			b.WriteString(mutedStyle.Italic(true).Render("No jobs loaded"))
+			b.WriteString("\n")
+		}
+		b.WriteString(ui.RenderStatus(detail.Error))
+		return b.String()
+	}
+	
+	nameWidth := runewidth.StringWidth("JOB")
+	runnerWidth := runewidth.StringWidth("RUNNER")
+	for _, job := range detail.WorkflowJobs {
+		if w := runewidth.StringWidth(job.GetName()); w > nameWidth {
+			nameWidth = w
+		}
+		if w := runewidth.StringWidth(job.GetRunnerName()); w > runnerWidth {
+			runnerWidth = w
+		}
+	}
+	if nameWidth > 50 {
+		nameWidth = 50
+	}
+	if runnerWidth > 30 {
+		runnerWidth = 30
+	}
+	statusWidth := 12
+	
+	header := fmt.Sprintf("  %s %s %s %s",
+		ui.padString("JOB", nameWidth),
+		ui.padString("STATUS", statusWidth),
+		ui.padString("RUNNER", runnerWidth),
+		"DURATION")
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true).Render(header))
+	b.WriteString("\n")
+	
+	if len(detail.WorkflowJobs) == 0 {
+		b.WriteString(mutedStyle.Italic(true).Render("No jobs found for this run"))
+		b.WriteString("\n")
+	}
+	
+	for i, job := range detail.WorkflowJobs {
+		status := ui.workflowJobStatus(job.GetStatus(), job.GetConclusion())
+		runner := job.GetRunnerName()
+		if runner == "" {
+			runner = "-"
+		}
+		
+		name := ui.padString(ui.truncate(job.GetName(), nameWidth), nameWidth)
+		statusCell := ui.padString(status, statusWidth)
+		runnerCell := ui.padString(ui.truncate(runner, runnerWidth), runnerWidth)
+		duration := ui.formatSpan(job.StartedAt, job.CompletedAt)
+		
+		if i == detail.Cursor {
+			row := fmt.Sprintf("> %s %s %s %s", name, statusCell, runnerCell, duration)
+			b.WriteString(lipgloss.NewStyle().Background(lipgloss.Color("4")).Foreground(lipgloss.Color("15")).Render(row))
+			b.WriteString("\n")
+			
+			// Expand the steps of the selected job
+			for _, step := range job.Steps {
+				stepStatus := ui.workflowJobStatus(step.GetStatus(), step.GetConclusion())
+				icon := ui.colorStatus(stepStatus, ui.stepIcon(stepStatus))
+				stepName := ui.truncate(step.GetName(), nameWidth+statusWidth)
+				b.WriteString(fmt.Sprintf("    %s %s %s\n",
+					icon,
+					ui.padString(stepName, nameWidth+statusWidth),
+					mutedStyle.Render(ui.formatSpan(step.StartedAt, step.CompletedAt))))
+			}
+			continue
+		}
+		
+		b.WriteString(fmt.Sprintf("  %s %s %s %s\n", name, ui.colorStatus(status, statusCell), runnerCell, duration))
+	}
+	
+	b.WriteString("\n")
+	b.WriteString(ui.RenderStatus(detail.Error))
+	
+	return b.String()
+}
+
+// RenderLogViewer renders the log viewer of a workflow job
+func (ui *UIComponents) RenderLogViewer(viewer *LogViewer) string {
+	var b strings.Builder
+	
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Bold(true).Render(viewer.Title))
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("Keys: [↑↓/pgup/pgdn] scroll [/] search [n/N] next/prev match [tab] select group [space] fold [z/Z] fold/unfold all [t]imestamps [r]eload [esc] back"))
+	b.WriteString("\n\n")
+	
+	switch {
+	case viewer.IsLoading():
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("Downloading logs..."))
+		b.WriteString("\n")
+		return b.String()
+	case viewer.Error() != "":
+		b.WriteString(ui.RenderStatus(viewer.Error()))
+		return b.String()
+	}
+	
+	b.WriteString(viewer.View())
+	b.WriteString("\n\n")
+	
+	status := fmt.Sprintf("%3.0f%%", viewer.ScrollPercent()*100)
+	if search := viewer.SearchView(); search != "" {
+		status = search + "  " + mutedStyle.Render(status)
+	} else {
+		status = mutedStyle.Render(status)
+	}
+	b.WriteString(status)
+	
+	return b.String()
 }
 
 // RenderCancelConfirm renders the cancel confirmation popup with interactive selection
@@ -366,6 +685,109 @@ func (ui *UIComponents) RenderCancelConfirm(job scanner.JobStatus, selection int
 		Align(lipgloss.Center).
 		Render("This action cannot be undone!")
 	
+	buttons := ui.renderConfirmButtons(selection)
+	
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Align(lipgloss.Center).
+		Render("Use ←/→ to select, Enter to confirm, Esc to cancel")
+	
+	content := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n\n%s", title, jobInfo, warning, buttons, instructions)
+	
+	return confirmStyle.Render(content)
+}
+
+// Helper functions
+
+// renderReviewComment renders the review comment input and the message preview
+func (ui *UIComponents) renderReviewComment(popup ReviewPopup) (string, string) {
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	if popup.Editing {
+		labelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Bold(true)
+	}
+	
+	commentInput := lipgloss.NewStyle().
+		Align(lipgloss.Left).
+		Render(labelStyle.Render("Comment:") + "\n" + popup.Comment)
+	
+	messagePreview := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("6")).
+		Background(lipgloss.Color("8")).
+		Padding(0, 1).
+		Align(lipgloss.Center).
+		Render(fmt.Sprintf("Message: %s", popup.Message))
+	
+	return commentInput, messagePreview
+}
+
+// renderEnvironmentChecklist renders the pending environments of the approval popup
+func (ui *UIComponents) renderEnvironmentChecklist(popup ReviewPopup) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	
+	if popup.Environments == nil {
+		return mutedStyle.Render("Loading pending environments...")
+	}
+	
+	nameWidth := 0
+	for _, env := range popup.Environments {
+		if w := runewidth.StringWidth(env.Name); w > nameWidth {
+			nameWidth = w
+		}
+	}
+	
+	lines := []string{"Environments:"}
+	for i, env := range popup.Environments {
+		pointer := "  "
+		if i == popup.EnvironmentCursor && !popup.Editing {
+			pointer = "> "
+		}
+		
+		check := "[ ]"
+		if env.Selected {
+			check = "[x]"
+		}
+		
+		details := []string{}
+		if env.WaitTimer > 0 {
+			details = append(details, fmt.Sprintf("wait %dm", env.WaitTimer))
+		}
+		if len(env.Reviewers) > 0 {
+			details = append(details, "reviewers: "+strings.Join(env.Reviewers, ", "))
+		}
+		if !env.CanApprove {
+			details = append(details, "not a reviewer")
+		}
+		
+		line := fmt.Sprintf("%s%s %s  %s", pointer, check, ui.padString(env.Name, nameWidth), strings.Join(details, " | "))
+		if !env.CanApprove {
+			line = mutedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	
+	return lipgloss.NewStyle().
+		Align(lipgloss.Left).
+		Render(strings.Join(lines, "\n"))
+}
+
+// renderReviewInstructions renders the key hints for the current popup step
+func (ui *UIComponents) renderReviewInstructions(popup ReviewPopup) string {
+	text := "Use ←/→ to select, Enter to confirm, Tab to edit comment, Esc to cancel"
+	if popup.Environments != nil {
+		text = "↑/↓ and Space to pick environments, ←/→ to select, Enter to confirm, Tab to edit comment, Esc to cancel"
+	}
+	if popup.Editing {
+		text = "Type a comment, Enter to continue, Esc to cancel"
+	}
+	
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Align(lipgloss.Center).
+		Render(text)
+}
+
+// renderConfirmButtons renders the interactive No/Yes buttons shared by confirmation popups
+func (ui *UIComponents) renderConfirmButtons(selection int) string {
 	// Create interactive Yes/No buttons with consistent width
 	buttonWidth := 8
 	
@@ -415,22 +837,11 @@ func (ui *UIComponents) RenderCancelConfirm(job scanner.JobStatus, selection int
 		yesButton,
 	)
 	
-	buttons := lipgloss.NewStyle().
+	return lipgloss.NewStyle().
 		Align(lipgloss.Center).
 		Render(buttonContainer)
-	
-	instructions := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
-		Align(lipgloss.Center).
-		Render("Use ←/→ to select, Enter to confirm, Esc to cancel")
-	
-	content := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n\n%s", title, jobInfo, warning, buttons, instructions)
-	
-	return confirmStyle.Render(content)
 }
 
-// Helper functions
-
 func (ui *UIComponents) getConnectionStatus(loading bool, errorMsg string) string {
 	if loading {
 		return lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("Connecting")
@@ -441,6 +852,25 @@ func (ui *UIComponents) getConnectionStatus(loading bool, errorMsg string) strin
 	}
 }
 
+// getRateLimitInfo shows the remaining API quota, in yellow when it runs low and
+// in red while requests are paused until the rate limit resets
+func (ui *UIComponents) getRateLimitInfo(stats githubclient.APIStats) string {
+	if stats.RateLimit == 0 {
+		return ""
+	}
+	
+	quota := fmt.Sprintf("  API: %d/%d", stats.RateRemaining, stats.RateLimit)
+	switch {
+	case !stats.PausedUntil.IsZero():
+		wait := time.Until(stats.PausedUntil).Round(time.Second)
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(fmt.Sprintf("%s (rate limited, resumes in %s)", quota, wait))
+	case stats.RateRemaining < stats.RateLimit/10:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(quota)
+	default:
+		return quota
+	}
+}
+
 func (ui *UIComponents) getScanInfo(progress monitor.ScanProgress) string {
 	var scanInfo string
 	if progress.TotalRepos > 0 {
@@ -496,11 +926,11 @@ func (ui *UIComponents) getTimerInfo(progress monitor.ScanProgress) string {
 }
 
 func (ui *UIComponents) getKeyBindings() string {
-	keyBindings := "Keys: [t]oggle view [r]efresh [a]pprove [c]ancel [o]pen browser [h]elp [q]uit [↑↓] navigate"
+	keyBindings := "Keys: [t]oggle view [r]efresh [a]pprove [x]reject [c]ancel [space] select [m]ine [/]filter [:]command [s]ort [enter] details [o]pen browser [h]elp [q]uit [↑↓] navigate"
 	return lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(keyBindings)
 }
 
-func (ui *UIComponents) renderTableHeader(b *strings.Builder, repoWidth, jobWidth, idWidth, statusWidth, branchWidth, actorWidth, ageWidth int) {
+func (ui *UIComponents) renderTableHeader(b *strings.Builder, sortKey scanner.SortKey, descending bool, repoWidth, jobWidth, idWidth, statusWidth, branchWidth, actorWidth, ageWidth int) {
 	headerStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("15")).
 		Bold(true)
@@ -511,18 +941,29 @@ func (ui *UIComponents) renderTableHeader(b *strings.Builder, repoWidth, jobWidt
 	// Create properly padded headers
 	var headers []string
 	for i, config := range configs {
-		headers = append(headers, ui.padString(config.Header, widths[i]))
+		headers = append(headers, ui.padString(ui.sortHeader(config, sortKey, descending), widths[i]))
 	}
-	ageHeader := ui.padString("AGE", ageWidth)
+	ageHeader := ui.padString(ui.sortHeader(ColumnConfig{Header: "AGE", SortKey: scanner.SortByAge}, sortKey, descending), ageWidth)
 	headers = append(headers, ageHeader)
 	
 	// Apply styling to entire header row to maintain alignment
-	headerRow := strings.Join(headers, " ")
+	headerRow := ui.padString("", markerWidth) + " " + strings.Join(headers, " ")
 	
 	b.WriteString(headerStyle.Render(headerRow))
 	b.WriteString("\n")
 }
 
+// sortHeader appends the sort direction indicator to the header of the sorted column
+func (ui *UIComponents) sortHeader(config ColumnConfig, sortKey scanner.SortKey, descending bool) string {
+	if sortKey == scanner.SortByDefault || config.SortKey != sortKey {
+		return config.Header
+	}
+	if descending {
+		return config.Header + " ▼"
+	}
+	return config.Header + " ▲"
+}
+
 func (ui *UIComponents) renderTableRow(b *strings.Builder, job scanner.JobStatus, i, cursor int, vm ViewManagerInterface, repoWidth, jobWidth, idWidth, statusWidth, branchWidth, actorWidth, ageWidth int) {
 	// Truncate and pad columns
 	repo := ui.padString(ui.truncate(job.Repository, repoWidth), repoWidth)
@@ -532,10 +973,12 @@ func (ui *UIComponents) renderTableRow(b *strings.Builder, job scanner.JobStatus
 	branch := ui.padString(ui.truncate(job.Branch, branchWidth), branchWidth)
 	actor := ui.padString(ui.truncate(job.Actor, actorWidth), actorWidth)
 	age := ui.padString(ui.formatAge(job.StartedAt), ageWidth)
+	selected := vm.IsJobSelected(job)
+	marker := ui.rowMarker(job, selected)
 	
 	// Build row string
-	rowString := fmt.Sprintf("%s %s %s %s %s %s %s",
-		repo, jobName, jobID, status, branch, actor, age)
+	rowString := fmt.Sprintf("%s %s %s %s %s %s %s %s",
+		marker, repo, jobName, jobID, status, branch, actor, age)
 	
 	// Apply styles based on priority: cursor > newly highlighted > completed > normal
 	if i == cursor {
@@ -573,15 +1016,112 @@ func (ui *UIComponents) renderTableRow(b *strings.Builder, job scanner.JobStatus
 				statusColored = status
 			}
 			
+			// Selected runs get a magenta marker, runs the current user cannot approve a red one
+			markerColored := ui.colorRowMarker(job, selected)
+			
 			// Build row with only status column colored
-			b.WriteString(fmt.Sprintf("%s %s %s %s %s %s %s",
-				repo, jobName, jobID, statusColored, branch, actor, age))
+			b.WriteString(fmt.Sprintf("%s %s %s %s %s %s %s %s",
+				markerColored, repo, jobName, jobID, statusColored, branch, actor, age))
 		}
 	}
 	
 	b.WriteString("\n")
 }
 
+// workflowJobStatus returns the conclusion of a finished job or step, otherwise its status
+func (ui *UIComponents) workflowJobStatus(status, conclusion string) string {
+	if status == "completed" && conclusion != "" {
+		return conclusion
+	}
+	return status
+}
+
+// colorStatus renders text in the color used for the given status
+func (ui *UIComponents) colorStatus(status, text string) string {
+	switch status {
+	case "waiting", "queued", "pending":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(text)
+	case "in_progress":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Render(text)
+	case "completed", "success":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render(text)
+	case "failure", "timed_out":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(text)
+	case "cancelled", "skipped":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(text)
+	default:
+		return text
+	}
+}
+
+// stepIcon returns the icon shown in front of a workflow step
+func (ui *UIComponents) stepIcon(status string) string {
+	switch status {
+	case "success":
+		return "✓"
+	case "failure", "timed_out":
+		return "✗"
+	case "cancelled":
+		return "⊘"
+	case "skipped":
+		return "-"
+	case "in_progress":
+		return "●"
+	default:
+		return "○"
+	}
+}
+
+// formatSpan returns the duration between start and end, or until now if not finished
+func (ui *UIComponents) formatSpan(start, end *github.Timestamp) string {
+	if start == nil || start.IsZero() {
+		return "-"
+	}
+	
+	if end == nil || end.IsZero() {
+		return ui.formatDuration(time.Since(start.Time)) + "…"
+	}
+	return ui.formatDuration(end.Sub(start.Time))
+}
+
+// formatDuration formats a duration as 1h2m, 3m4s or 5s
+func (ui *UIComponents) formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	} else if d < time.Hour {
+		return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// rowMarker returns the marker shown in the leftmost column of a row: the
+// selection mark followed by the not-a-reviewer mark
+func (ui *UIComponents) rowMarker(job scanner.JobStatus, selected bool) string {
+	selection, reviewer := " ", " "
+	if selected {
+		selection = "*"
+	}
+	if job.Status == "waiting" && !job.IsApprovable() {
+		reviewer = "!"
+	}
+	return selection + reviewer
+}
+
+// colorRowMarker returns the row marker with each mark in its own color
+func (ui *UIComponents) colorRowMarker(job scanner.JobStatus, selected bool) string {
+	marker := ui.rowMarker(job, selected)
+	selection := marker[:1]
+	if selected {
+		selection = lipgloss.NewStyle().Foreground(lipgloss.Color("5")).Bold(true).Render(selection)
+	}
+	return selection + lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true).Render(marker[1:])
+}
+
 // Text formatting utilities
 
 func (ui *UIComponents) truncate(s string, width int) string {
@@ -659,23 +1199,73 @@ func (ui *UIComponents) formatAge(startedAt *time.Time) string {
 	}
 }
 
+// ReviewPopup holds the state rendered by the approval and rejection popups
+type ReviewPopup struct {
+	Job       scanner.JobStatus
+	Selection int    // 0 = No, 1 = Yes
+	Editing   bool   // Whether the comment input has focus
+	Comment   string // Rendered comment input
+	Message   string // Preview of the review comment sent to GitHub
+	
+	// Environment checklist, approval popup only. Nil while loading.
+	Environments      []EnvironmentOption
+	EnvironmentCursor int
+}
+
+// BulkPopup holds the state shown in the bulk approve/cancel confirmation popup
+type BulkPopup struct {
+	Action    string // BulkApprove or BulkCancel
+	Jobs      []scanner.JobStatus
+	Selection int    // 0 = No, 1 = Yes
+	Editing   bool   // Whether the comment input has focus, approve only
+	Comment   string // Rendered comment input, approve only
+	Message   string // Preview of the review comment for the first run, approve only
+}
+
+// EnvironmentOption is a pending environment shown in the approval checklist
+type EnvironmentOption struct {
+	Name       string
+	WaitTimer  int // Minutes
+	CanApprove bool
+	Reviewers  []string
+	Selected   bool
+}
+
+// JobDetail holds the state rendered by the job detail pane
+type JobDetail struct {
+	Job          scanner.JobStatus
+	WorkflowJobs []*github.WorkflowJob // Nil until the first load completes
+	Cursor       int
+	Loading      bool
+	LoadedAt     time.Time
+	AutoRefresh  bool // Whether the run is still in progress
+	Error        string
+}
+
+// markerWidth is the width of the leftmost row marker column
+const markerWidth = 2
+
+// sortIndicatorWidth is the room reserved in each header for the " ▲" or " ▼" sort indicator
+const sortIndicatorWidth = 2
+
 // Column configuration structure
 type ColumnConfig struct {
 	Header       string
 	MaxWidth     int
 	MinimumWidth int
+	SortKey      scanner.SortKey
 }
 
 // Column width calculations
 
 func (ui *UIComponents) getColumnConfigs() []ColumnConfig {
 	return []ColumnConfig{
-		{Header: "REPOSITORY", MaxWidth: 30, MinimumWidth: 12},
-		{Header: "JOB NAME", MaxWidth: 50, MinimumWidth: 20},
-		{Header: "RNO", MaxWidth: 10, MinimumWidth: 4},
-		{Header: "STATUS", MaxWidth: 15, MinimumWidth: 10},
-		{Header: "BRANCH", MaxWidth: 25, MinimumWidth: 10},
-		{Header: "ACTOR", MaxWidth: 20, MinimumWidth: 10},
+		{Header: "REPOSITORY", MaxWidth: 30, MinimumWidth: 12, SortKey: scanner.SortByRepository},
+		{Header: "JOB NAME", MaxWidth: 50, MinimumWidth: 20, SortKey: scanner.SortByWorkflow},
+		{Header: "RNO", MaxWidth: 10, MinimumWidth: 4, SortKey: scanner.SortByRunNumber},
+		{Header: "STATUS", MaxWidth: 15, MinimumWidth: 10, SortKey: scanner.SortByStatus},
+		{Header: "BRANCH", MaxWidth: 25, MinimumWidth: 10, SortKey: scanner.SortByBranch},
+		{Header: "ACTOR", MaxWidth: 20, MinimumWidth: 10, SortKey: scanner.SortByActor},
 	}
 }
 
@@ -683,9 +1273,9 @@ func (ui *UIComponents) calculateColumnWidths(jobs []scanner.JobStatus) []int {
 	configs := ui.getColumnConfigs()
 	widths := make([]int, len(configs))
 	
-	// Initialize with header widths
+	// Initialize with header widths, leaving room for the sort indicator
 	for i, config := range configs {
-		widths[i] = runewidth.StringWidth(config.Header)
+		widths[i] = runewidth.StringWidth(config.Header) + sortIndicatorWidth
 	}
 	
 	// Calculate max content width for each column
@@ -724,8 +1314,8 @@ func (ui *UIComponents) calculateColumnWidths(jobs []scanner.JobStatus) []int {
 }
 
 func (ui *UIComponents) calculateAgeColumnWidth(jobs []scanner.JobStatus) int {
-	// Minimum width for "AGE" header
-	minWidth := runewidth.StringWidth("AGE")
+	// Minimum width for "AGE" header and its sort indicator
+	minWidth := runewidth.StringWidth("AGE") + sortIndicatorWidth
 	
 	// Calculate max content width for age column
 	for _, job := range jobs {
@@ -3,8 +3,14 @@ package tui
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v60/github"
+	githubclient "github.com/younsl/cocd/pkg/github"
 	"github.com/younsl/cocd/pkg/scanner"
 )
 
@@ -35,6 +41,52 @@ type ViewManager struct {
 	showApprovalConfirm bool
 	approvalTargetJob   *scanner.JobStatus
 	approvalSelection   int
+	
+	approvalEnvironments []*githubclient.PendingDeployment
+	approvalEnvSelected  []bool
+	approvalEnvCursor    int
+	
+	showRejectionConfirm bool
+	rejectionTargetJob   *scanner.JobStatus
+	rejectionSelection   int
+	
+	commentInput   textinput.Model
+	editingComment bool
+	
+	approvableOnly bool
+	
+	showJobDetail   bool
+	detailTargetJob *scanner.JobStatus
+	detailJobs      []*github.WorkflowJob
+	detailLoading   bool
+	detailLoadedAt  time.Time
+	// detailRequestedAt is when the workflow jobs were last requested, whether or not that succeeded
+	detailRequestedAt time.Time
+	detailCursor    int
+	
+	logViewer *LogViewer
+	logJobID  int64
+	
+	filterInput   textinput.Model
+	editingFilter bool
+	filter        *scanner.Filter
+	filterError   string
+	
+	sortKey        scanner.SortKey
+	sortDescending bool
+	
+	repositoryScope string
+	
+	commandInput       textinput.Model
+	editingCommand     bool
+	commandSuggestions []string
+	
+	selectedJobs map[string]scanner.JobStatus
+	
+	showBulkConfirm bool
+	bulkAction      string
+	bulkTargetJobs  []scanner.JobStatus
+	bulkSelection   int
 }
 
 // NewViewManager creates a new view manager
@@ -46,13 +98,51 @@ func NewViewManager() ViewManagerInterface {
 		recentJobsPerPage: 50,
 		completedJobs:     make(map[string]scanner.JobStatus),
 		previousJobs:      make(map[string]scanner.JobStatus),
+		commentInput:      newCommentInput(),
+		filterInput:       newFilterInput(),
+		commandInput:      newCommandInput(),
+		selectedJobs:      make(map[string]scanner.JobStatus),
 	}
 }
 
+// newCommentInput creates the text input used for review comments
+func newCommentInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "reason or ticket number (optional)"
+	input.CharLimit = 500
+	input.Width = 50
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return input
+}
+
+// newFilterInput creates the text input used for the job table filter
+func newFilterInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "text, /regex/ or field:value (repo, workflow, branch, actor, event, status)"
+	input.CharLimit = 200
+	input.Width = 80
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return input
+}
+
+// newCommandInput creates the text input used for the : command prompt
+func newCommandInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "command (Tab to complete, Esc to cancel)"
+	input.CharLimit = 200
+	input.Width = 80
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return input
+}
+
 // SwitchToView switches to the specified view
 func (vm *ViewManager) SwitchToView(viewType ViewType) {
 	vm.currentView = viewType
 	vm.cursor = 0
+	vm.ClearSelection()
 	if viewType == ViewRecent {
 		vm.recentJobsPage = 0
 	}
@@ -151,6 +241,16 @@ func (vm *ViewManager) TrackCompletedJobs(currentJobs, newJobs []scanner.JobStat
 	}
 }
 
+// RestoreCompletedJobs adds jobs that left the pending view in an earlier session
+func (vm *ViewManager) RestoreCompletedJobs(jobs []scanner.JobStatus) {
+	for _, job := range jobs {
+		key := jobKey(job)
+		if _, exists := vm.completedJobs[key]; !exists {
+			vm.completedJobs[key] = job
+		}
+	}
+}
+
 // GetCombinedPendingJobs returns combined pending and completed jobs
 func (vm *ViewManager) GetCombinedPendingJobs(jobs []scanner.JobStatus) []scanner.JobStatus {
 	combinedJobs := make([]scanner.JobStatus, len(jobs))
@@ -158,12 +258,12 @@ func (vm *ViewManager) GetCombinedPendingJobs(jobs []scanner.JobStatus) []scanne
 	
 	existingKeys := make(map[string]bool)
 	for _, job := range jobs {
-		key := fmt.Sprintf("%s:%d:%d", job.Repository, job.RunID, job.ID)
+		key := jobKey(job)
 		existingKeys[key] = true
 	}
 	
 	for _, completedJob := range vm.completedJobs {
-		key := fmt.Sprintf("%s:%d:%d", completedJob.Repository, completedJob.RunID, completedJob.ID)
+		key := jobKey(completedJob)
 		if !existingKeys[key] {
 			combinedJobs = append(combinedJobs, completedJob)
 		}
@@ -208,7 +308,7 @@ func (vm *ViewManager) IsJobCompleted(job scanner.JobStatus) bool {
 
 // isJobCompleted checks if a job is in the completed jobs map
 func (vm *ViewManager) isJobCompleted(job scanner.JobStatus) bool {
-	key := fmt.Sprintf("%s:%d:%d", job.Repository, job.RunID, job.ID)
+	key := jobKey(job)
 	_, exists := vm.completedJobs[key]
 	return exists
 }
@@ -225,6 +325,430 @@ func (vm *ViewManager) GetMaxCursorPosition(pendingJobs, recentJobs []scanner.Jo
 	}
 }
 
+// ToggleApprovableOnly toggles hiding waiting runs the current user cannot approve
+func (vm *ViewManager) ToggleApprovableOnly() {
+	vm.approvableOnly = !vm.approvableOnly
+	vm.cursor = 0
+}
+
+// IsApprovableOnly returns whether only runs the current user can approve are shown
+func (vm *ViewManager) IsApprovableOnly() bool {
+	return vm.approvableOnly
+}
+
+// StartFilterEditing focuses the filter input
+func (vm *ViewManager) StartFilterEditing() {
+	vm.editingFilter = true
+	vm.filterInput.CursorEnd()
+	vm.filterInput.Focus()
+}
+
+// StopFilterEditing blurs the filter input and keeps the last valid filter
+func (vm *ViewManager) StopFilterEditing() {
+	vm.editingFilter = false
+	vm.filterInput.Blur()
+	vm.filterInput.SetValue(vm.filter.String())
+	vm.filterError = ""
+}
+
+// IsEditingFilter returns whether the filter input has focus
+func (vm *ViewManager) IsEditingFilter() bool {
+	return vm.editingFilter
+}
+
+// UpdateFilterInput forwards a key message to the filter input and applies the query as it is typed
+func (vm *ViewManager) UpdateFilterInput(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	vm.filterInput, cmd = vm.filterInput.Update(msg)
+	
+	// Keep the previous filter until the query parses again
+	if err := vm.applyFilter(vm.filterInput.Value()); err != nil {
+		vm.filterError = err.Error()
+		return cmd
+	}
+	vm.filterError = ""
+	return cmd
+}
+
+// SetFilter replaces the filter with a parsed query; an empty query clears it
+func (vm *ViewManager) SetFilter(query string) error {
+	if err := vm.applyFilter(query); err != nil {
+		return err
+	}
+	vm.filterInput.SetValue(vm.filter.String())
+	return nil
+}
+
+func (vm *ViewManager) applyFilter(query string) error {
+	filter, err := scanner.ParseFilter(query)
+	if err != nil {
+		return err
+	}
+	if filter.String() != vm.filter.String() {
+		vm.filter = filter
+		vm.resetPosition()
+	}
+	return nil
+}
+
+// ClearFilter removes the filter and leaves filter editing
+func (vm *ViewManager) ClearFilter() {
+	hadFilter := vm.filter != nil
+	vm.filter = nil
+	vm.filterInput.SetValue("")
+	vm.editingFilter = false
+	vm.filterInput.Blur()
+	vm.filterError = ""
+	if hadFilter {
+		vm.resetPosition()
+	}
+}
+
+// GetFilter returns the active filter, or nil when no filter is set
+func (vm *ViewManager) GetFilter() *scanner.Filter {
+	return vm.filter
+}
+
+// GetFilterError returns why the typed filter query could not be parsed
+func (vm *ViewManager) GetFilterError() string {
+	return vm.filterError
+}
+
+// GetFilterInputView renders the filter input
+func (vm *ViewManager) GetFilterInputView() string {
+	return vm.filterInput.View()
+}
+
+// CycleSortKey switches the job table to the next sort key in ascending order
+func (vm *ViewManager) CycleSortKey() {
+	for i, key := range scanner.SortKeys {
+		if key == vm.sortKey {
+			vm.sortKey = scanner.SortKeys[(i+1)%len(scanner.SortKeys)]
+			break
+		}
+	}
+	vm.sortDescending = false
+}
+
+// ToggleSortDirection flips the job table between ascending and descending order
+func (vm *ViewManager) ToggleSortDirection() {
+	if vm.sortKey == scanner.SortByDefault {
+		return
+	}
+	vm.sortDescending = !vm.sortDescending
+}
+
+// GetSort returns the job table sort key and direction
+func (vm *ViewManager) GetSort() (scanner.SortKey, bool) {
+	return vm.sortKey, vm.sortDescending
+}
+
+// FocusJob moves the cursor to a job by its position in the unpaginated job list
+func (vm *ViewManager) FocusJob(index int) {
+	if index < 0 {
+		return
+	}
+	if vm.currentView == ViewRecent {
+		vm.recentJobsPage = index / vm.recentJobsPerPage
+		vm.cursor = index % vm.recentJobsPerPage
+		return
+	}
+	vm.cursor = index
+}
+
+// SetRepositoryScope limits the job table to a single repository; an empty name shows all repositories
+func (vm *ViewManager) SetRepositoryScope(repo string) {
+	if repo != vm.repositoryScope {
+		vm.repositoryScope = repo
+		vm.resetPosition()
+	}
+}
+
+// GetRepositoryScope returns the repository the job table is limited to, if any
+func (vm *ViewManager) GetRepositoryScope() string {
+	return vm.repositoryScope
+}
+
+// StartCommandInput opens an empty command prompt
+func (vm *ViewManager) StartCommandInput() {
+	vm.editingCommand = true
+	vm.commandInput.SetValue("")
+	vm.commandSuggestions = nil
+	vm.commandInput.Focus()
+}
+
+// StopCommandInput closes the command prompt
+func (vm *ViewManager) StopCommandInput() {
+	vm.editingCommand = false
+	vm.commandSuggestions = nil
+	vm.commandInput.Blur()
+}
+
+// IsEditingCommand returns whether the command prompt is open
+func (vm *ViewManager) IsEditingCommand() bool {
+	return vm.editingCommand
+}
+
+// UpdateCommandInput forwards a key message to the command prompt
+func (vm *ViewManager) UpdateCommandInput(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	vm.commandInput, cmd = vm.commandInput.Update(msg)
+	vm.commandSuggestions = nil
+	return cmd
+}
+
+// GetCommandInput returns the text typed into the command prompt
+func (vm *ViewManager) GetCommandInput() string {
+	return vm.commandInput.Value()
+}
+
+// SetCommandInput replaces the text of the command prompt, e.g. after completion
+func (vm *ViewManager) SetCommandInput(value string) {
+	vm.commandInput.SetValue(value)
+	vm.commandInput.CursorEnd()
+}
+
+// SetCommandSuggestions stores the completion candidates shown under the command prompt
+func (vm *ViewManager) SetCommandSuggestions(suggestions []string) {
+	vm.commandSuggestions = suggestions
+}
+
+// GetCommandSuggestions returns the completion candidates shown under the command prompt
+func (vm *ViewManager) GetCommandSuggestions() []string {
+	return vm.commandSuggestions
+}
+
+// GetCommandInputView renders the command prompt
+func (vm *ViewManager) GetCommandInputView() string {
+	return vm.commandInput.View()
+}
+
+// ToggleJobSelection marks or unmarks a job for a bulk action
+func (vm *ViewManager) ToggleJobSelection(job scanner.JobStatus) {
+	key := jobKey(job)
+	if _, ok := vm.selectedJobs[key]; ok {
+		delete(vm.selectedJobs, key)
+		return
+	}
+	vm.selectedJobs[key] = job
+}
+
+// ToggleAllSelection marks every given job, or unmarks them all when they are already marked
+func (vm *ViewManager) ToggleAllSelection(jobs []scanner.JobStatus) {
+	allSelected := len(jobs) > 0
+	for _, job := range jobs {
+		if !vm.IsJobSelected(job) {
+			allSelected = false
+			break
+		}
+	}
+	
+	for _, job := range jobs {
+		if allSelected {
+			delete(vm.selectedJobs, jobKey(job))
+		} else {
+			vm.selectedJobs[jobKey(job)] = job
+		}
+	}
+}
+
+// ClearSelection unmarks every job
+func (vm *ViewManager) ClearSelection() {
+	vm.selectedJobs = make(map[string]scanner.JobStatus)
+}
+
+// IsJobSelected returns whether a job is marked for a bulk action
+func (vm *ViewManager) IsJobSelected(job scanner.JobStatus) bool {
+	_, ok := vm.selectedJobs[jobKey(job)]
+	return ok
+}
+
+// GetSelectedJobs returns the marked jobs ordered by repository and run number
+func (vm *ViewManager) GetSelectedJobs() []scanner.JobStatus {
+	jobs := make([]scanner.JobStatus, 0, len(vm.selectedJobs))
+	for _, job := range vm.selectedJobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Repository != jobs[j].Repository {
+			return jobs[i].Repository < jobs[j].Repository
+		}
+		return jobs[i].RunNumber < jobs[j].RunNumber
+	})
+	return jobs
+}
+
+// ShowBulkConfirm shows the confirmation popup for approving or cancelling several runs
+func (vm *ViewManager) ShowBulkConfirm(action string, jobs []scanner.JobStatus) {
+	vm.showBulkConfirm = true
+	vm.bulkAction = action
+	vm.bulkTargetJobs = jobs
+	vm.bulkSelection = 0
+	if action == BulkApprove {
+		vm.resetCommentInput()
+	}
+}
+
+// HideBulkConfirm hides the bulk confirmation popup
+func (vm *ViewManager) HideBulkConfirm() {
+	vm.showBulkConfirm = false
+	vm.bulkAction = ""
+	vm.bulkTargetJobs = nil
+	vm.bulkSelection = 0
+	vm.SetEditingComment(false)
+}
+
+// IsShowingBulkConfirm returns whether the bulk confirmation popup is showing
+func (vm *ViewManager) IsShowingBulkConfirm() bool {
+	return vm.showBulkConfirm
+}
+
+// GetBulkAction returns the action of the bulk confirmation popup
+func (vm *ViewManager) GetBulkAction() string {
+	return vm.bulkAction
+}
+
+// GetBulkTargetJobs returns the runs the bulk action applies to
+func (vm *ViewManager) GetBulkTargetJobs() []scanner.JobStatus {
+	return vm.bulkTargetJobs
+}
+
+// SetBulkSelection sets the bulk confirmation selection (0 = No, 1 = Yes)
+func (vm *ViewManager) SetBulkSelection(selection int) {
+	if selection == 0 || selection == 1 {
+		vm.bulkSelection = selection
+	}
+}
+
+// GetBulkSelection returns the bulk confirmation selection (0 = No, 1 = Yes)
+func (vm *ViewManager) GetBulkSelection() int {
+	return vm.bulkSelection
+}
+
+// IsBulkConfirmed returns true if "Yes" is selected
+func (vm *ViewManager) IsBulkConfirmed() bool {
+	return vm.bulkSelection == 1
+}
+
+// resetPosition moves the cursor and recent jobs page back to the start after the job list changes
+func (vm *ViewManager) resetPosition() {
+	vm.cursor = 0
+	vm.recentJobsPage = 0
+}
+
+// ShowJobDetail opens the detail pane for a workflow run
+func (vm *ViewManager) ShowJobDetail(job scanner.JobStatus) {
+	vm.showJobDetail = true
+	vm.detailTargetJob = &job
+	vm.detailJobs = nil
+	vm.detailLoading = true
+	vm.detailLoadedAt = time.Time{}
+	vm.detailRequestedAt = time.Now()
+	vm.detailCursor = 0
+}
+
+// HideJobDetail closes the detail pane
+func (vm *ViewManager) HideJobDetail() {
+	vm.showJobDetail = false
+	vm.detailTargetJob = nil
+	vm.detailJobs = nil
+	vm.detailLoading = false
+	vm.detailCursor = 0
+	vm.CloseLogViewer()
+}
+
+// IsShowingJobDetail returns whether the detail pane is showing
+func (vm *ViewManager) IsShowingJobDetail() bool {
+	return vm.showJobDetail
+}
+
+// GetDetailTargetJob returns the run shown in the detail pane
+func (vm *ViewManager) GetDetailTargetJob() *scanner.JobStatus {
+	return vm.detailTargetJob
+}
+
+// SetDetailJobs stores the workflow jobs of the run shown in the detail pane
+func (vm *ViewManager) SetDetailJobs(jobs []*github.WorkflowJob) {
+	vm.detailJobs = jobs
+	vm.detailLoading = false
+	vm.detailLoadedAt = time.Now()
+	if vm.detailCursor >= len(jobs) {
+		vm.detailCursor = 0
+	}
+}
+
+// GetDetailJobs returns the workflow jobs of the run shown in the detail pane
+func (vm *ViewManager) GetDetailJobs() []*github.WorkflowJob {
+	return vm.detailJobs
+}
+
+// SetDetailLoading marks whether workflow jobs are being fetched
+func (vm *ViewManager) SetDetailLoading(loading bool) {
+	vm.detailLoading = loading
+	if loading {
+		vm.detailRequestedAt = time.Now()
+	}
+}
+
+// IsDetailLoading returns whether workflow jobs are being fetched
+func (vm *ViewManager) IsDetailLoading() bool {
+	return vm.detailLoading
+}
+
+// GetDetailLoadedAt returns when the workflow jobs were last fetched
+func (vm *ViewManager) GetDetailLoadedAt() time.Time {
+	return vm.detailLoadedAt
+}
+
+// GetDetailRequestedAt returns when the workflow jobs were last requested, including failed requests
+func (vm *ViewManager) GetDetailRequestedAt() time.Time {
+	return vm.detailRequestedAt
+}
+
+// MoveDetailCursor moves the selected workflow job up or down
+func (vm *ViewManager) MoveDetailCursor(direction int) {
+	newCursor := vm.detailCursor + direction
+	if newCursor >= len(vm.detailJobs) {
+		newCursor = len(vm.detailJobs) - 1
+	}
+	if newCursor < 0 {
+		newCursor = 0
+	}
+	vm.detailCursor = newCursor
+}
+
+// GetDetailCursor returns the index of the selected workflow job
+func (vm *ViewManager) GetDetailCursor() int {
+	return vm.detailCursor
+}
+
+// OpenLogViewer opens the log viewer for a workflow job of the run in the detail pane
+func (vm *ViewManager) OpenLogViewer(title string, jobID int64, width, height int) {
+	vm.logViewer = NewLogViewer(title, width, height)
+	vm.logJobID = jobID
+}
+
+// CloseLogViewer closes the log viewer and returns to the detail pane
+func (vm *ViewManager) CloseLogViewer() {
+	vm.logViewer = nil
+	vm.logJobID = 0
+}
+
+// IsShowingLogViewer returns whether the log viewer is showing
+func (vm *ViewManager) IsShowingLogViewer() bool {
+	return vm.logViewer != nil
+}
+
+// GetLogViewer returns the open log viewer, or nil
+func (vm *ViewManager) GetLogViewer() *LogViewer {
+	return vm.logViewer
+}
+
+// GetLogJobID returns the ID of the workflow job shown in the log viewer
+func (vm *ViewManager) GetLogJobID() int64 {
+	return vm.logJobID
+}
+
 // ShowCancelConfirm shows the cancel confirmation popup
 func (vm *ViewManager) ShowCancelConfirm(job scanner.JobStatus) {
 	vm.showCancelConfirm = true
@@ -271,6 +795,10 @@ func (vm *ViewManager) ShowApprovalConfirm(job scanner.JobStatus) {
 	vm.showApprovalConfirm = true
 	vm.approvalTargetJob = &job
 	vm.approvalSelection = 0
+	vm.approvalEnvironments = nil
+	vm.approvalEnvSelected = nil
+	vm.approvalEnvCursor = 0
+	vm.resetCommentInput()
 }
 
 // HideApprovalConfirm hides the approval confirmation popup
@@ -278,6 +806,10 @@ func (vm *ViewManager) HideApprovalConfirm() {
 	vm.showApprovalConfirm = false
 	vm.approvalTargetJob = nil
 	vm.approvalSelection = 0
+	vm.approvalEnvironments = nil
+	vm.approvalEnvSelected = nil
+	vm.approvalEnvCursor = 0
+	vm.SetEditingComment(false)
 }
 
 // IsShowingApprovalConfirm returns whether approval confirmation is showing
@@ -307,6 +839,152 @@ func (vm *ViewManager) IsApprovalConfirmed() bool {
 	return vm.approvalSelection == 1
 }
 
+// SetApprovalEnvironments sets the environments shown in the approval checklist.
+// Environments the current user can approve are selected by default.
+func (vm *ViewManager) SetApprovalEnvironments(deployments []*githubclient.PendingDeployment) {
+	vm.approvalEnvironments = deployments
+	vm.approvalEnvSelected = make([]bool, len(deployments))
+	for i, pd := range deployments {
+		vm.approvalEnvSelected[i] = pd.CurrentUserCanApprove
+	}
+	vm.approvalEnvCursor = 0
+}
+
+// GetApprovalEnvironments returns the environments in the approval checklist, nil while loading
+func (vm *ViewManager) GetApprovalEnvironments() []*githubclient.PendingDeployment {
+	return vm.approvalEnvironments
+}
+
+// MoveApprovalEnvironmentCursor moves the checklist cursor up or down
+func (vm *ViewManager) MoveApprovalEnvironmentCursor(direction int) {
+	newCursor := vm.approvalEnvCursor + direction
+	if newCursor >= len(vm.approvalEnvironments) {
+		newCursor = len(vm.approvalEnvironments) - 1
+	}
+	if newCursor < 0 {
+		newCursor = 0
+	}
+	vm.approvalEnvCursor = newCursor
+}
+
+// GetApprovalEnvironmentCursor returns the checklist cursor position
+func (vm *ViewManager) GetApprovalEnvironmentCursor() int {
+	return vm.approvalEnvCursor
+}
+
+// ToggleApprovalEnvironment toggles the environment under the checklist cursor.
+// Environments the current user cannot approve stay unselected.
+func (vm *ViewManager) ToggleApprovalEnvironment() {
+	i := vm.approvalEnvCursor
+	if i < 0 || i >= len(vm.approvalEnvironments) {
+		return
+	}
+	if !vm.approvalEnvironments[i].CurrentUserCanApprove {
+		return
+	}
+	vm.approvalEnvSelected[i] = !vm.approvalEnvSelected[i]
+}
+
+// IsApprovalEnvironmentSelected returns whether the environment at index is checked
+func (vm *ViewManager) IsApprovalEnvironmentSelected(index int) bool {
+	if index < 0 || index >= len(vm.approvalEnvSelected) {
+		return false
+	}
+	return vm.approvalEnvSelected[index]
+}
+
+// GetSelectedEnvironmentIDs returns the IDs of the checked environments
+func (vm *ViewManager) GetSelectedEnvironmentIDs() []int64 {
+	var environmentIDs []int64
+	for i, pd := range vm.approvalEnvironments {
+		if vm.approvalEnvSelected[i] && pd.Environment.ID != nil {
+			environmentIDs = append(environmentIDs, *pd.Environment.ID)
+		}
+	}
+	return environmentIDs
+}
+
+// ShowRejectionConfirm shows the rejection confirmation popup
+func (vm *ViewManager) ShowRejectionConfirm(job scanner.JobStatus) {
+	vm.showRejectionConfirm = true
+	vm.rejectionTargetJob = &job
+	vm.rejectionSelection = 0
+	vm.resetCommentInput()
+}
+
+// HideRejectionConfirm hides the rejection confirmation popup
+func (vm *ViewManager) HideRejectionConfirm() {
+	vm.showRejectionConfirm = false
+	vm.rejectionTargetJob = nil
+	vm.rejectionSelection = 0
+	vm.SetEditingComment(false)
+}
+
+// IsShowingRejectionConfirm returns whether rejection confirmation is showing
+func (vm *ViewManager) IsShowingRejectionConfirm() bool {
+	return vm.showRejectionConfirm
+}
+
+// GetRejectionTargetJob returns the job to be rejected
+func (vm *ViewManager) GetRejectionTargetJob() *scanner.JobStatus {
+	return vm.rejectionTargetJob
+}
+
+// SetRejectionSelection sets the rejection selection (0 = No, 1 = Yes)
+func (vm *ViewManager) SetRejectionSelection(selection int) {
+	if selection == 0 || selection == 1 {
+		vm.rejectionSelection = selection
+	}
+}
+
+// GetRejectionSelection returns the current selection (0 = No, 1 = Yes)
+func (vm *ViewManager) GetRejectionSelection() int {
+	return vm.rejectionSelection
+}
+
+// IsRejectionConfirmed returns true if "Yes" is selected
+func (vm *ViewManager) IsRejectionConfirmed() bool {
+	return vm.rejectionSelection == 1
+}
+
+// resetCommentInput clears the review comment and gives the input focus
+func (vm *ViewManager) resetCommentInput() {
+	vm.commentInput.Reset()
+	vm.SetEditingComment(true)
+}
+
+// IsEditingComment returns whether the review comment input has focus
+func (vm *ViewManager) IsEditingComment() bool {
+	return vm.editingComment
+}
+
+// SetEditingComment focuses or blurs the review comment input
+func (vm *ViewManager) SetEditingComment(editing bool) {
+	vm.editingComment = editing
+	if editing {
+		vm.commentInput.Focus()
+	} else {
+		vm.commentInput.Blur()
+	}
+}
+
+// UpdateCommentInput forwards a key message to the review comment input
+func (vm *ViewManager) UpdateCommentInput(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	vm.commentInput, cmd = vm.commentInput.Update(msg)
+	return cmd
+}
+
+// GetComment returns the review comment typed by the user
+func (vm *ViewManager) GetComment() string {
+	return strings.TrimSpace(vm.commentInput.Value())
+}
+
+// GetCommentInputView renders the review comment input
+func (vm *ViewManager) GetCommentInputView() string {
+	return vm.commentInput.View()
+}
+
 // MarkNewlyScannedJobs marks new jobs and sets up highlighting
 func (vm *ViewManager) MarkNewlyScannedJobs(jobs []scanner.JobStatus) []scanner.JobStatus {
 	now := time.Now()
@@ -314,13 +992,13 @@ func (vm *ViewManager) MarkNewlyScannedJobs(jobs []scanner.JobStatus) []scanner.
 	
 	currentJobsMap := make(map[string]scanner.JobStatus)
 	for _, job := range jobs {
-		key := fmt.Sprintf("%s:%d:%d", job.Repository, job.RunID, job.ID)
+		key := jobKey(job)
 		currentJobsMap[key] = job
 	}
 	
 	updatedJobs := make([]scanner.JobStatus, len(jobs))
 	for i, job := range jobs {
-		key := fmt.Sprintf("%s:%d:%d", job.Repository, job.RunID, job.ID)
+		key := jobKey(job)
 		
 		if _, existsInPrevious := vm.previousJobs[key]; !existsInPrevious {
 			job.IsNewlyScanned = true
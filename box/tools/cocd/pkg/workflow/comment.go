@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultCommentTemplate is the review comment sent with approvals and rejections
+// when approval.comment_template is not configured
+const DefaultCommentTemplate = "Remote {action} by {user} via cocd at {timestamp}"
+
+// ReviewCommentData holds the values substituted into a review comment template
+type ReviewCommentData struct {
+	Action     string // "approved" or "rejected"
+	User       string
+	Repository string
+	Workflow   string
+	Branch     string
+	RunNumber  int
+	RunID      int64
+	Timestamp  string
+	Comment    string // free text typed by the reviewer
+}
+
+// FormatReviewComment renders a review comment template. When the template has no
+// {comment} placeholder, a non-empty comment is placed above the rendered template.
+func FormatReviewComment(template string, data ReviewCommentData) string {
+	if template == "" {
+		template = DefaultCommentTemplate
+	}
+
+	comment := strings.TrimSpace(data.Comment)
+
+	replacer := strings.NewReplacer(
+		"{comment}", comment,
+		"{action}", data.Action,
+		"{user}", data.User,
+		"{repo}", data.Repository,
+		"{workflow}", data.Workflow,
+		"{branch}", data.Branch,
+		"{run_number}", fmt.Sprintf("%d", data.RunNumber),
+		"{run_id}", fmt.Sprintf("%d", data.RunID),
+		"{timestamp}", data.Timestamp,
+	)
+	message := strings.TrimSpace(replacer.Replace(template))
+
+	if comment != "" && !strings.Contains(template, "{comment}") {
+		return comment + "\n\n" + message
+	}
+	return message
+}
@@ -0,0 +1,202 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	ghclient "github.com/younsl/cocd/pkg/github"
+	"github.com/younsl/cocd/pkg/scanner"
+)
+
+// Review actions substituted for {action} in review comments
+const (
+	ActionApproved = "approved"
+	ActionRejected = "rejected"
+)
+
+var (
+	// ErrCancelInProgress means GitHub accepted the cancellation but the run has not stopped yet
+	ErrCancelInProgress = errors.New("cancellation in progress")
+	// ErrNoPendingDeployments means the run is not waiting on any environment
+	ErrNoPendingDeployments = errors.New("no pending deployments found for this workflow")
+)
+
+// Client is the subset of the GitHub client used to review and cancel workflow runs
+type Client interface {
+	GetWorkflowRun(ctx context.Context, repo string, runID int64) (*github.WorkflowRun, *github.Response, error)
+	GetPendingDeployments(ctx context.Context, repo string, runID int64) ([]*ghclient.PendingDeployment, *github.Response, error)
+	ApprovePendingDeployment(ctx context.Context, repo string, runID int64, environmentIDs []int64, comment string) (*github.Response, error)
+	RejectPendingDeployment(ctx context.Context, repo string, runID int64, environmentIDs []int64, comment string) (*github.Response, error)
+	CancelWorkflowRun(ctx context.Context, repo string, runID int64) (*github.Response, error)
+	GetAuthenticatedUser(ctx context.Context) (*github.User, *github.Response, error)
+}
+
+// Service approves, rejects and cancels workflow runs. It is shared by the TUI
+// and the approve and cancel commands.
+type Service struct {
+	client          Client
+	commentTemplate string
+	timezone        string
+
+	userMu   sync.Mutex
+	username string
+}
+
+// NewService creates a service. commentTemplate and timezone format the review
+// comments and fall back to the defaults when empty.
+func NewService(client Client, commentTemplate, timezone string) *Service {
+	return &Service{
+		client:          client,
+		commentTemplate: commentTemplate,
+		timezone:        timezone,
+	}
+}
+
+// GetRun fetches a workflow run as a JobStatus
+func (s *Service) GetRun(ctx context.Context, repo string, runID int64) (scanner.JobStatus, error) {
+	run, _, err := s.client.GetWorkflowRun(ctx, repo, runID)
+	if err != nil {
+		return scanner.JobStatus{}, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+	return scanner.NewJobStatus(repo, run), nil
+}
+
+// PendingDeployments returns the environments a run is waiting on
+func (s *Service) PendingDeployments(ctx context.Context, job scanner.JobStatus) ([]*ghclient.PendingDeployment, error) {
+	pendingDeployments, _, err := s.client.GetPendingDeployments(ctx, job.Repository, job.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending deployments: %w", err)
+	}
+	if len(pendingDeployments) == 0 {
+		return nil, ErrNoPendingDeployments
+	}
+	return pendingDeployments, nil
+}
+
+// ApprovableDeployments returns the pending deployments of a run the current user
+// can approve, limited to one environment when environment is not empty
+func (s *Service) ApprovableDeployments(ctx context.Context, job scanner.JobStatus, environment string) ([]*ghclient.PendingDeployment, error) {
+	pendingDeployments, err := s.PendingDeployments(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	var approvable []*ghclient.PendingDeployment
+	for _, pd := range pendingDeployments {
+		if !pd.CurrentUserCanApprove || pd.Environment.ID == nil {
+			continue
+		}
+		if environment != "" && !strings.EqualFold(pd.EnvironmentName(), environment) {
+			continue
+		}
+		approvable = append(approvable, pd)
+	}
+	return approvable, nil
+}
+
+// Approve approves the given pending environments of a run
+func (s *Service) Approve(ctx context.Context, job scanner.JobStatus, environmentIDs []int64, comment string) error {
+	if len(environmentIDs) == 0 {
+		return fmt.Errorf("no environments selected for approval")
+	}
+
+	_, err := s.client.ApprovePendingDeployment(ctx, job.Repository, job.RunID, environmentIDs, s.ReviewComment(ctx, ActionApproved, job, comment))
+	return err
+}
+
+// Reject rejects every pending environment of a run
+func (s *Service) Reject(ctx context.Context, job scanner.JobStatus, comment string) error {
+	pendingDeployments, err := s.PendingDeployments(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	environmentIDs := EnvironmentIDs(pendingDeployments)
+	if len(environmentIDs) == 0 {
+		return fmt.Errorf("no environment IDs found in pending deployments")
+	}
+
+	_, err = s.client.RejectPendingDeployment(ctx, job.Repository, job.RunID, environmentIDs, s.ReviewComment(ctx, ActionRejected, job, comment))
+	return err
+}
+
+// Cancel cancels a run. It returns ErrCancelInProgress when GitHub is still
+// stopping jobs that were already scheduled.
+func (s *Service) Cancel(ctx context.Context, job scanner.JobStatus) error {
+	_, err := s.client.CancelWorkflowRun(ctx, job.Repository, job.RunID)
+	if err != nil && strings.Contains(err.Error(), "job scheduled on GitHub side") {
+		return ErrCancelInProgress
+	}
+	return err
+}
+
+// ReviewComment renders the comment sent to GitHub when approving or rejecting a run
+func (s *Service) ReviewComment(ctx context.Context, action string, job scanner.JobStatus, comment string) string {
+	return FormatReviewComment(s.commentTemplate, ReviewCommentData{
+		Action:     action,
+		User:       s.currentUser(ctx),
+		Repository: job.Repository,
+		Workflow:   job.WorkflowName,
+		Branch:     job.Branch,
+		RunNumber:  job.RunNumber,
+		RunID:      job.RunID,
+		Timestamp:  s.reviewTimestamp(),
+		Comment:    comment,
+	})
+}
+
+// currentUser returns the authenticated user's login, fetched once and cached
+func (s *Service) currentUser(ctx context.Context) string {
+	s.userMu.Lock()
+	defer s.userMu.Unlock()
+
+	if s.username == "" {
+		if user, _, err := s.client.GetAuthenticatedUser(ctx); err == nil && user.GetLogin() != "" {
+			s.username = user.GetLogin()
+		}
+	}
+
+	if s.username == "" {
+		return "unknown"
+	}
+	return s.username
+}
+
+func (s *Service) reviewTimestamp() string {
+	timezone := s.timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return time.Now().In(loc).Format("2006-01-02 15:04:05 MST")
+}
+
+// EnvironmentIDs returns the IDs of the environments of pending deployments
+func EnvironmentIDs(deployments []*ghclient.PendingDeployment) []int64 {
+	var ids []int64
+	for _, pd := range deployments {
+		if pd.Environment.ID != nil {
+			ids = append(ids, *pd.Environment.ID)
+		}
+	}
+	return ids
+}
+
+// EnvironmentNames returns the names of the environments of pending deployments
+func EnvironmentNames(deployments []*ghclient.PendingDeployment) []string {
+	names := make([]string, 0, len(deployments))
+	for _, pd := range deployments {
+		names = append(names, pd.EnvironmentName())
+	}
+	return names
+}